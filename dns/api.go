@@ -0,0 +1,97 @@
+package dns
+
+import (
+	"context"
+	"io"
+)
+
+// ZoneAPI is the interface of the zones API implemented by ZoneClient, to
+// inject and mock the client in services.
+type ZoneAPI interface {
+	List(ctx context.Context, opts ZoneListOpts) ([]*Zone, *Response, error)
+	GetByID(ctx context.Context, id string) (*Zone, *Response, error)
+	Create(ctx context.Context, opts ZoneCreateOpts) (*Zone, *Response, error)
+	Update(ctx context.Context, zone *Zone, opts ZoneUpdateOpts) (*Zone, *Response, error)
+	Delete(ctx context.Context, zone *Zone) (*Response, error)
+	Import(ctx context.Context, zone *Zone, file io.Reader) (*Zone, *Response, error)
+	Export(ctx context.Context, zone *Zone) (io.Reader, *Response, error)
+	ValidateFile(ctx context.Context, file io.Reader) (*ValidatedZoneFile, *Response, error)
+}
+
+// RecordAPI is the interface of the records API implemented by RecordClient.
+type RecordAPI interface {
+	List(ctx context.Context, opts RecordListOpts) ([]*Record, *Response, error)
+	GetByID(ctx context.Context, id string) (*Record, *Response, error)
+	Create(ctx context.Context, opts RecordCreateOpts) (*Record, *Response, error)
+	Update(ctx context.Context, rec *Record, opts RecordUpdateOpts) (*Record, *Response, error)
+	Delete(ctx context.Context, rec *Record) (*Response, error)
+	BulkCreate(ctx context.Context, bulkOpts []RecordCreateOpts) (*RecordBulkCreateResponse, *Response, error)
+	BulkUpdate(ctx context.Context, bulkOpts []RecordBulkUpdateOpts) (*RecordBulkUpdateResponse, *Response, error)
+}
+
+// PrimaryServerAPI is the interface of the primary servers API implemented
+// by PrimaryServerClient.
+type PrimaryServerAPI interface {
+	List(ctx context.Context, opts PrimaryServerListOpts) ([]*PrimaryServer, *Response, error)
+	GetByID(ctx context.Context, id string) (*PrimaryServer, *Response, error)
+	Create(ctx context.Context, opts PrimaryServerCreateOpts) (*PrimaryServer, *Response, error)
+	Update(ctx context.Context, server *PrimaryServer, opts PrimaryServerUpdateOpts) (*PrimaryServer, *Response, error)
+	Delete(ctx context.Context, server *PrimaryServer) (*Response, error)
+}
+
+// API holds the sub-clients used by the functions working across them, like
+// PlanZone and SecondaryZoneClient, so cached clients or fakes can be used.
+type API struct {
+	Zone          ZoneAPI
+	Record        RecordAPI
+	PrimaryServer PrimaryServerAPI
+}
+
+// API returns the sub-clients of the client.
+func (c *Client) API() API {
+	return API{Zone: c.Zone, Record: c.Record, PrimaryServer: c.PrimaryServer}
+}
+
+// listAllZones returns all zones with the given parameters, requesting all
+// pages.
+func listAllZones(ctx context.Context, api ZoneAPI, opts ZoneListOpts) ([]*Zone, *Response, error) {
+	var all []*Zone
+	for {
+		zones, resp, err := api.List(ctx, opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		all = append(all, zones...)
+
+		if !resp.HasNextPage() {
+			return all, resp, nil
+		}
+		opts.Page = resp.NextPage()
+	}
+}
+
+// listAllRecords returns all records with the given parameters, requesting
+// all pages.
+func listAllRecords(ctx context.Context, api RecordAPI, opts RecordListOpts) ([]*Record, *Response, error) {
+	var all []*Record
+	for {
+		records, resp, err := api.List(ctx, opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		all = append(all, records...)
+
+		if !resp.HasNextPage() {
+			return all, resp, nil
+		}
+		opts.Page = resp.NextPage()
+	}
+}
+
+var (
+	_ ZoneAPI          = (*ZoneClient)(nil)
+	_ ZoneAPI          = (*CachedZoneClient)(nil)
+	_ RecordAPI        = (*RecordClient)(nil)
+	_ RecordAPI        = (*CachedRecordClient)(nil)
+	_ PrimaryServerAPI = (*PrimaryServerClient)(nil)
+)
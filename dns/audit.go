@@ -0,0 +1,206 @@
+package dns
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// AuditOperation is the kind of a mutating call recorded in an AuditEvent.
+type AuditOperation string
+
+const (
+	AuditZoneCreate          AuditOperation = "zone.create"
+	AuditZoneUpdate          AuditOperation = "zone.update"
+	AuditZoneDelete          AuditOperation = "zone.delete"
+	AuditZoneImport          AuditOperation = "zone.import"
+	AuditRecordCreate        AuditOperation = "record.create"
+	AuditRecordUpdate        AuditOperation = "record.update"
+	AuditRecordDelete        AuditOperation = "record.delete"
+	AuditRecordBulkCreate    AuditOperation = "record.bulk_create"
+	AuditRecordBulkUpdate    AuditOperation = "record.bulk_update"
+	AuditPrimaryServerCreate AuditOperation = "primary_server.create"
+	AuditPrimaryServerUpdate AuditOperation = "primary_server.update"
+	AuditPrimaryServerDelete AuditOperation = "primary_server.delete"
+)
+
+// AuditOutcome is the outcome of a mutating call recorded in an AuditEvent.
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+)
+
+// AuditEvent records a mutating call of the client.
+type AuditEvent struct {
+	Time        time.Time      `json:"time"`
+	Application string         `json:"application,omitempty"`
+	Operation   AuditOperation `json:"operation"`
+	ZoneID      string         `json:"zone_id,omitempty"`
+	ZoneName    string         `json:"zone_name,omitempty"`
+	// Request holds the options of the call, e.g. the RecordUpdateOpts.
+	Request interface{} `json:"request,omitempty"`
+	// Before holds the zone, record or primary server as stored in the API
+	// before an update, delete or import, read right before the call, bulk
+	// updates have the records to update as Before. It is empty when it could
+	// not be read. After holds the one returned by the API, bulk calls have
+	// the returned records as After.
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+	// RequestID is the id the API assigned to the request, if any.
+	RequestID string       `json:"request_id,omitempty"`
+	DryRun    bool         `json:"dry_run,omitempty"`
+	Outcome   AuditOutcome `json:"outcome"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// AuditSink receives the audit events of a client, see WithAuditSink.
+type AuditSink interface {
+	Audit(event AuditEvent) error
+}
+
+// AuditFunc is an AuditSink calling the function.
+type AuditFunc func(event AuditEvent) error
+
+// Audit calls f(event).
+func (f AuditFunc) Audit(event AuditEvent) error {
+	return f(event)
+}
+
+// WithAuditSink configures the client to record every create, update, delete
+// and import, whether successful or not, to the given sinks. To record the
+// state before an update, delete or import, the affected resource is read
+// from the API before the call. The events are sent to the sinks in order
+// after the call completed, synchronously before the call returns, so a slow
+// sink slows down every write; sinks writing to slow destinations should
+// buffer the events. Errors and panics of the sinks are logged to the debug
+// writer, if any, and do not affect the call.
+func WithAuditSink(sinks ...AuditSink) ClientOption {
+	return func(client *Client) {
+		client.auditSinks = append(client.auditSinks, sinks...)
+	}
+}
+
+// auditing reports whether audit sinks are configured. The state before a
+// call is only read from the API in that case.
+func (c *Client) auditing() bool {
+	return len(c.auditSinks) > 0
+}
+
+// audit completes event with the outcome of a call on zone and sends it to
+// the audit sinks.
+func (c *Client) audit(event AuditEvent, zone *Zone, resp *Response, err error) {
+	if len(c.auditSinks) == 0 {
+		return
+	}
+
+	event.Time = time.Now()
+	event.Application = c.applicationName
+	if zone != nil {
+		event.ZoneID = zone.ID
+		event.ZoneName = zone.Name
+	}
+	if resp != nil {
+		event.RequestID = resp.RequestID
+		event.DryRun = resp.DryRun
+	}
+	event.Request = nilIfEmpty(event.Request)
+	event.Before = nilIfEmpty(event.Before)
+	event.After = nilIfEmpty(event.After)
+	event.Outcome = AuditSuccess
+	if err != nil {
+		event.Outcome = AuditFailure
+		event.Error = err.Error()
+	}
+
+	for _, sink := range c.auditSinks {
+		c.sendAudit(sink, event)
+	}
+}
+
+func (c *Client) sendAudit(sink AuditSink, event AuditEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.log(fmt.Sprintf("hetzner-dns: audit sink panicked on %s: %v", event.Operation, r))
+		}
+	}()
+
+	if err := sink.Audit(event); err != nil {
+		c.log(fmt.Sprintf("hetzner-dns: audit sink failed on %s: %s", event.Operation, err))
+	}
+}
+
+// nilIfEmpty returns nil for nil pointers and slices, which would be
+// recorded as null otherwise.
+func nilIfEmpty(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Slice:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+
+	return v
+}
+
+// bulkZone returns the zone of the records to create when they are all in
+// the same zone.
+func bulkZone(bulkOpts []RecordCreateOpts) *Zone {
+	zones := make([]*Zone, len(bulkOpts))
+	for i, opts := range bulkOpts {
+		zones[i] = opts.Zone
+	}
+
+	return commonZone(zones)
+}
+
+// bulkUpdateZone returns the zone of the records to update when they are all
+// in the same zone.
+func bulkUpdateZone(bulkOpts []RecordBulkUpdateOpts) *Zone {
+	zones := make([]*Zone, len(bulkOpts))
+	for i, opts := range bulkOpts {
+		zones[i] = opts.Zone
+	}
+
+	return commonZone(zones)
+}
+
+func commonZone(zones []*Zone) *Zone {
+	if len(zones) == 0 || zones[0] == nil {
+		return nil
+	}
+	for _, zone := range zones[1:] {
+		if zone == nil || zone.ID != zones[0].ID {
+			return nil
+		}
+	}
+
+	return zones[0]
+}
+
+// JSONAuditSink writes audit events as JSON lines.
+type JSONAuditSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONAuditSink returns a sink writing every audit event as a line of
+// JSON to w, e.g. a file opened with os.O_APPEND.
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{enc: json.NewEncoder(w)}
+}
+
+// Audit writes event as a line of JSON.
+func (s *JSONAuditSink) Audit(event AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.enc.Encode(event)
+}
@@ -0,0 +1,63 @@
+//go:build go1.21
+
+package dns
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogAuditSink logs audit events to a slog.Logger.
+type SlogAuditSink struct {
+	logger *slog.Logger
+}
+
+// NewSlogAuditSink returns a sink logging every audit event to logger, at
+// info level when the call succeeded and at warn level when it failed.
+func NewSlogAuditSink(logger *slog.Logger) *SlogAuditSink {
+	return &SlogAuditSink{logger: logger}
+}
+
+// Audit logs event.
+func (s *SlogAuditSink) Audit(event AuditEvent) error {
+	level := slog.LevelInfo
+	if event.Outcome == AuditFailure {
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.Time("time", event.Time),
+		slog.String("operation", string(event.Operation)),
+		slog.String("outcome", string(event.Outcome)),
+	}
+	if event.Application != "" {
+		attrs = append(attrs, slog.String("application", event.Application))
+	}
+	if event.ZoneID != "" {
+		attrs = append(attrs, slog.String("zone_id", event.ZoneID))
+	}
+	if event.ZoneName != "" {
+		attrs = append(attrs, slog.String("zone_name", event.ZoneName))
+	}
+	if event.Request != nil {
+		attrs = append(attrs, slog.Any("request", event.Request))
+	}
+	if event.Before != nil {
+		attrs = append(attrs, slog.Any("before", event.Before))
+	}
+	if event.After != nil {
+		attrs = append(attrs, slog.Any("after", event.After))
+	}
+	if event.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", event.RequestID))
+	}
+	if event.DryRun {
+		attrs = append(attrs, slog.Bool("dry_run", true))
+	}
+	if event.Error != "" {
+		attrs = append(attrs, slog.String("error", event.Error))
+	}
+
+	s.logger.LogAttrs(context.Background(), level, "hetzner-dns: "+string(event.Operation), attrs...)
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build go1.21
+
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogAuditSink(t *testing.T) {
+	as := newAssert(t)
+
+	var buf bytes.Buffer
+	sink := NewSlogAuditSink(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	err := sink.Audit(AuditEvent{
+		Operation: AuditRecordDelete,
+		ZoneID:    "zone1",
+		Before:    &Record{ID: "rec1", Name: "www"},
+		Outcome:   AuditFailure,
+		Error:     "not found",
+	})
+	if !as.NoError(err) {
+		return
+	}
+
+	var line map[string]interface{}
+	if !as.NoError(json.Unmarshal(buf.Bytes(), &line)) {
+		return
+	}
+	as.EqStr("WARN", line["level"].(string))
+	as.EqStr("record.delete", line["operation"].(string))
+	as.EqStr("zone1", line["zone_id"].(string))
+	as.EqStr("not found", line["error"].(string))
+	as.EqStr("rec1", line["before"].(map[string]interface{})["ID"].(string))
+}
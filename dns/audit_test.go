@@ -0,0 +1,173 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	recID := api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 0)
+	zone := &Zone{ID: zoneID, Name: "hetzner.com"}
+
+	var events []AuditEvent
+	var lines, debug bytes.Buffer
+	env.Client = NewClient(
+		WithEndpoint(env.Server.URL),
+		WithApplication("dns-sync", "1.0"),
+		WithDebugWriter(&debug),
+		WithAuditSink(
+			AuditFunc(func(event AuditEvent) error { return errors.New("sink down") }),
+			AuditFunc(func(event AuditEvent) error { panic("sink broken") }),
+			NewJSONAuditSink(&lines),
+			AuditFunc(func(event AuditEvent) error {
+				events = append(events, event)
+				return nil
+			}),
+		),
+	)
+
+	rec, _, err := env.Client.Record.GetByID(env.Context, recID)
+	if !as.NoError(err) {
+		return
+	}
+
+	updated, _, err := env.Client.Record.Update(env.Context, rec, RecordUpdateOpts{Name: "www", Type: RecordTypeA, Value: "192.0.2.2", Zone: zone})
+	if !as.NoError(err) {
+		return
+	}
+	_, err = env.Client.Record.Delete(env.Context, &Record{ID: "unknown", Zone: zone})
+	as.Error(err)
+	_, _, err = env.Client.Record.BulkCreate(env.Context, []RecordCreateOpts{
+		{Name: "a", Type: RecordTypeA, Value: "192.0.2.3", Zone: zone},
+		{Name: "b", Type: RecordTypeA, Value: "192.0.2.4", Zone: zone},
+	})
+	as.NoError(err)
+
+	if !as.EqInt(3, len(events)) {
+		return
+	}
+
+	update := events[0]
+	as.EqStr(string(AuditRecordUpdate), string(update.Operation))
+	as.EqStr(string(AuditSuccess), string(update.Outcome))
+	as.EqStr("dns-sync", update.Application)
+	as.EqStr(zoneID, update.ZoneID)
+	as.EqStr("hetzner.com", update.ZoneName)
+	as.EqStr("192.0.2.1", update.Before.(*Record).Value)
+	as.EqStr(updated.Value, update.After.(*Record).Value)
+	if update.Time.IsZero() {
+		t.Error("expected time")
+	}
+
+	del := events[1]
+	as.EqStr(string(AuditRecordDelete), string(del.Operation))
+	as.EqStr(string(AuditFailure), string(del.Outcome))
+	if del.After != nil || del.Error == "" {
+		t.Errorf("unexpected failure event %+v", del)
+	}
+
+	bulk := events[2]
+	as.EqStr(string(AuditRecordBulkCreate), string(bulk.Operation))
+	as.EqInt(2, len(bulk.After.([]*Record)))
+
+	// The JSON sink wrote one line per event.
+	jsonLines := strings.Split(strings.TrimSpace(lines.String()), "\n")
+	if as.EqInt(3, len(jsonLines)) {
+		var line map[string]interface{}
+		if as.NoError(json.Unmarshal([]byte(jsonLines[1]), &line)) {
+			as.EqStr("record.delete", line["operation"].(string))
+			as.EqStr("failure", line["outcome"].(string))
+			if _, ok := line["after"]; ok {
+				t.Error("unexpected after of failed delete")
+			}
+		}
+	}
+
+	// The failing sinks were logged and did not affect the calls.
+	as.EqInt(3, strings.Count(debug.String(), "audit sink failed on"))
+	as.EqInt(3, strings.Count(debug.String(), "audit sink panicked on"))
+}
+
+func TestAuditBeforeFromAPI(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	recID := api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 300)
+
+	var events []AuditEvent
+	env.Client = NewClient(
+		WithEndpoint(env.Server.URL),
+		WithAuditSink(AuditFunc(func(event AuditEvent) error {
+			events = append(events, event)
+			return nil
+		})),
+	)
+
+	// Only the id is known to the caller, the state is read from the API.
+	_, err := env.Client.Record.Delete(env.Context, &Record{ID: recID})
+	if !as.NoError(err) || !as.EqInt(1, len(events)) {
+		return
+	}
+
+	before, ok := events[0].Before.(*Record)
+	if !ok {
+		t.Fatalf("unexpected before %+v", events[0].Before)
+	}
+	as.EqStr("www", before.Name)
+	as.EqStr("192.0.2.1", before.Value)
+	as.EqInt(300, before.Ttl)
+	as.EqStr(zoneID, events[0].ZoneID)
+
+	apiID := api.AddRecord(zoneID, "api", RecordTypeA, "192.0.2.2", 0)
+	_, _, err = env.Client.Record.BulkUpdate(env.Context, []RecordBulkUpdateOpts{
+		{ID: apiID, Name: "api", Type: RecordTypeA, Value: "192.0.2.3", Zone: &Zone{ID: zoneID, Name: "hetzner.com"}},
+	})
+	if !as.NoError(err) || !as.EqInt(2, len(events)) {
+		return
+	}
+
+	bulkBefore, ok := events[1].Before.([]*Record)
+	if !ok || !as.EqInt(1, len(bulkBefore)) {
+		t.Fatalf("unexpected before %+v", events[1].Before)
+	}
+	as.EqStr(apiID, bulkBefore[0].ID)
+	as.EqStr("192.0.2.2", bulkBefore[0].Value)
+}
+
+func TestAuditDryRun(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	var events []AuditEvent
+	env.Client = NewClient(
+		WithEndpoint(env.Server.URL),
+		WithDebugWriter(&bytes.Buffer{}),
+		WithDryRun(),
+		WithAuditSink(AuditFunc(func(event AuditEvent) error {
+			events = append(events, event)
+			return nil
+		})),
+	)
+
+	_, _, err := env.Client.Zone.Create(env.Context, ZoneCreateOpts{Name: "hetzner.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || !events[0].DryRun || !strings.HasPrefix(events[0].ZoneID, DryRunIDPrefix) {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
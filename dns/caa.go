@@ -0,0 +1,516 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CAA property tags, see RFC 8659.
+const (
+	CAATagIssue     = "issue"
+	CAATagIssueWild = "issuewild"
+	CAATagIODEF     = "iodef"
+)
+
+// CAAFlagCritical is the issuer critical flag of a CAA property. CAs must not
+// issue if they don't understand the tag of a critical property.
+const CAAFlagCritical uint8 = 128
+
+// CAAProperty is the value of a single CAA record.
+type CAAProperty struct {
+	Flags uint8
+	Tag   string
+	Value string
+}
+
+// ParseCAAProperty parses the value of a CAA record, e.g.
+// `0 issue "letsencrypt.org"`.
+func ParseCAAProperty(value string) (*CAAProperty, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return nil, errors.New("caa: flags, tag and value required")
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("caa: invalid flags %s", fields[0])
+	}
+
+	tag := fields[1]
+	if !isCAATag(tag) {
+		return nil, fmt.Errorf("caa: invalid tag %s", tag)
+	}
+
+	rest := skipFields(value, 2)
+	if strings.HasPrefix(rest, `"`) {
+		unquoted, remainder, ok := unquoteTXTString(rest)
+		if !ok || strings.TrimSpace(remainder) != "" {
+			return nil, fmt.Errorf("caa: invalid value %s", rest)
+		}
+		rest = unquoted
+	}
+
+	return &CAAProperty{Flags: uint8(flags), Tag: strings.ToLower(tag), Value: rest}, nil
+}
+
+// isCAATag reports whether tag consists of ASCII letters and digits only.
+func isCAATag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the value of the CAA record.
+func (p *CAAProperty) String() string {
+	return fmt.Sprintf("%d %s %s", p.Flags, p.Tag, quoteTXT(p.Value))
+}
+
+// Critical reports whether the issuer critical flag is set.
+func (p *CAAProperty) Critical() bool {
+	return p.Flags&CAAFlagCritical != 0
+}
+
+// CAAIssuer is the value of an issue or issuewild property, allowing a CA to
+// issue certificates.
+type CAAIssuer struct {
+	// Domain is the issuer domain name of the CA, e.g. "letsencrypt.org".
+	// An empty domain allows no CA.
+	Domain string
+	// AccountURI restricts issuance to the account of the CA, see RFC 8657.
+	AccountURI string
+	// ValidationMethods restricts issuance to the domain validation methods,
+	// e.g. "dns-01", see RFC 8657.
+	ValidationMethods []string
+	// Parameters holds other parameters of the CA.
+	Parameters map[string]string
+	// Critical sets the issuer critical flag of the property.
+	Critical bool
+}
+
+// ParseCAAIssuer parses the value of an issue or issuewild property, e.g.
+// "letsencrypt.org; validationmethods=dns-01".
+func ParseCAAIssuer(value string) (*CAAIssuer, error) {
+	parts := strings.Split(value, ";")
+	issuer := &CAAIssuer{Domain: strings.TrimSpace(parts[0])}
+	if strings.ContainsAny(issuer.Domain, " \t=") {
+		return nil, fmt.Errorf("caa: invalid issuer %s", issuer.Domain)
+	}
+
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(part, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !ok || !isCAATag(key) || val == "" {
+			return nil, fmt.Errorf("caa: invalid parameter %s", part)
+		}
+
+		switch strings.ToLower(key) {
+		case "accounturi":
+			issuer.AccountURI = val
+		case "validationmethods":
+			issuer.ValidationMethods = splitList(val, ",")
+		default:
+			if issuer.Parameters == nil {
+				issuer.Parameters = map[string]string{}
+			}
+			issuer.Parameters[key] = val
+		}
+	}
+
+	return issuer, nil
+}
+
+// String returns the value of the issue or issuewild property.
+func (i *CAAIssuer) String() string {
+	params := []string{i.Domain}
+	if i.AccountURI != "" {
+		params = append(params, "accounturi="+i.AccountURI)
+	}
+	if len(i.ValidationMethods) > 0 {
+		params = append(params, "validationmethods="+strings.Join(i.ValidationMethods, ","))
+	}
+
+	keys := make([]string, 0, len(i.Parameters))
+	for key := range i.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		params = append(params, key+"="+i.Parameters[key])
+	}
+
+	if len(params) == 1 && i.Domain == "" {
+		return ";"
+	}
+
+	return strings.Join(params, "; ")
+}
+
+// Validate checks the issuer domain name, the account URI and the
+// validation methods.
+func (i *CAAIssuer) Validate() error {
+	if i.Domain != "" {
+		if _, err := asciiName(i.Domain); err != nil || strings.ContainsAny(i.Domain, " \t;=") {
+			return fmt.Errorf("caa: invalid issuer %s", i.Domain)
+		}
+	}
+	if i.AccountURI != "" {
+		u, err := url.Parse(i.AccountURI)
+		if err != nil || u.Scheme == "" {
+			return fmt.Errorf("caa: invalid account uri %s", i.AccountURI)
+		}
+	}
+	for _, method := range i.ValidationMethods {
+		if method == "" || strings.Trim(method, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+			return fmt.Errorf("caa: invalid validation method %s", method)
+		}
+	}
+	for key, val := range i.Parameters {
+		if !isCAATag(key) || val == "" || strings.ContainsAny(val, " \t;") {
+			return fmt.Errorf("caa: invalid parameter %s=%s", key, val)
+		}
+	}
+
+	return nil
+}
+
+// allows reports whether the issuer allows the issuance.
+func (i *CAAIssuer) allows(req CAAIssuance) bool {
+	if i.Domain == "" || !strings.EqualFold(strings.TrimSuffix(i.Domain, "."), strings.TrimSuffix(req.CA, ".")) {
+		return false
+	}
+	if i.AccountURI != "" && i.AccountURI != req.AccountURI {
+		return false
+	}
+	if len(i.ValidationMethods) == 0 {
+		return true
+	}
+	for _, method := range i.ValidationMethods {
+		if strings.EqualFold(method, req.ValidationMethod) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CAAPolicy is the set of CAA records of a name.
+type CAAPolicy struct {
+	// Issue holds the CAs allowed to issue certificates for the name and,
+	// without IssueWild, wildcard certificates.
+	Issue []*CAAIssuer
+	// IssueWild holds the CAs allowed to issue wildcard certificates. With
+	// IssueWild, Issue doesn't apply to wildcard certificates.
+	IssueWild []*CAAIssuer
+	// IODEF holds the mailto, http or https URLs to report invalid
+	// certificate requests to, see RFC 8659 section 4.4.
+	IODEF []string
+	// Other holds the properties with other tags.
+	Other []*CAAProperty
+}
+
+// ParseCAAPolicy parses the values of the CAA records of a name.
+func ParseCAAPolicy(values ...string) (*CAAPolicy, error) {
+	policy := &CAAPolicy{}
+	for _, value := range values {
+		prop, err := ParseCAAProperty(value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch prop.Tag {
+		case CAATagIssue, CAATagIssueWild:
+			issuer, err := ParseCAAIssuer(prop.Value)
+			if err != nil {
+				return nil, err
+			}
+			issuer.Critical = prop.Critical()
+			if prop.Tag == CAATagIssue {
+				policy.Issue = append(policy.Issue, issuer)
+			} else {
+				policy.IssueWild = append(policy.IssueWild, issuer)
+			}
+		case CAATagIODEF:
+			policy.IODEF = append(policy.IODEF, prop.Value)
+		default:
+			policy.Other = append(policy.Other, prop)
+		}
+	}
+
+	return policy, nil
+}
+
+// Properties returns the properties of the policy, one per record.
+func (p *CAAPolicy) Properties() []*CAAProperty {
+	var props []*CAAProperty
+	issuers := func(tag string, issuers []*CAAIssuer) {
+		for _, issuer := range issuers {
+			prop := &CAAProperty{Tag: tag, Value: issuer.String()}
+			if issuer.Critical {
+				prop.Flags = CAAFlagCritical
+			}
+			props = append(props, prop)
+		}
+	}
+	issuers(CAATagIssue, p.Issue)
+	issuers(CAATagIssueWild, p.IssueWild)
+	for _, iodef := range p.IODEF {
+		props = append(props, &CAAProperty{Tag: CAATagIODEF, Value: iodef})
+	}
+
+	return append(props, p.Other...)
+}
+
+// Validate checks the issuers, the IODEF URLs and the tags of the other
+// properties.
+func (p *CAAPolicy) Validate() error {
+	for _, issuer := range append(append([]*CAAIssuer(nil), p.Issue...), p.IssueWild...) {
+		if err := issuer.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, iodef := range p.IODEF {
+		u, err := url.Parse(iodef)
+		if err != nil || (u.Scheme != "mailto" && u.Scheme != "https" && u.Scheme != "http") {
+			return fmt.Errorf("caa: iodef %s must be a mailto, http or https url", iodef)
+		}
+	}
+	for _, prop := range p.Other {
+		if !isCAATag(prop.Tag) {
+			return fmt.Errorf("caa: invalid tag %s", prop.Tag)
+		}
+	}
+
+	return nil
+}
+
+// RecordCreateOpts returns the options to create the CAA records of the
+// policy with the given name in the zone.
+func (p *CAAPolicy) RecordCreateOpts(zone *Zone, name string) ([]RecordCreateOpts, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var opts []RecordCreateOpts
+	for _, prop := range p.Properties() {
+		opts = append(opts, RecordCreateOpts{Name: name, Type: RecordTypeCAA, Value: prop.String(), Zone: zone})
+	}
+
+	return opts, nil
+}
+
+// CAAIssuance is a certificate request checked against CAA policies.
+type CAAIssuance struct {
+	// Name is the name to issue the certificate for, relative to the zone
+	// or fully qualified. Wildcard names like "*.www" request a wildcard
+	// certificate.
+	Name string
+	// CA is the issuer domain name of the CA, e.g. "letsencrypt.org".
+	CA string
+	// AccountURI is the account of the CA requesting the certificate. It
+	// must be given for issuers restricted to an account.
+	AccountURI string
+	// ValidationMethod is the domain validation method of the request. It
+	// must be given for issuers restricted to validation methods.
+	ValidationMethod string
+}
+
+// Allows reports whether the policy allows the issuance. Without issuers for
+// the kind of certificate any CA is allowed, unless the policy has a critical
+// property with an unknown tag.
+func (p *CAAPolicy) Allows(req CAAIssuance) bool {
+	for _, prop := range p.Other {
+		if prop.Critical() {
+			return false
+		}
+	}
+
+	issuers := p.Issue
+	if IsWildcard(req.Name) && len(p.IssueWild) > 0 {
+		issuers = p.IssueWild
+	}
+	if len(issuers) == 0 {
+		return true
+	}
+	for _, issuer := range issuers {
+		if issuer.allows(req) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CAAResult is the result of CheckCAA.
+type CAAResult struct {
+	Allowed bool
+	// Name is the name, relative to the zone, at which the relevant CAA
+	// records were found, possibly through an alias. It is empty when no
+	// name in the zone has CAA records.
+	Name string
+	// Policy is the policy of the relevant CAA records, nil without records.
+	Policy *CAAPolicy
+}
+
+// maxCAAAliases limits the aliases CheckCAA follows for a name.
+const maxCAAAliases = 8
+
+// CheckCAA lists the records of the zone and checks if the CA may issue the
+// certificate, see CheckCAA.
+func (c RecordClient) CheckCAA(ctx context.Context, zone *Zone, req CAAIssuance) (*CAAResult, *Response, error) {
+	records, resp, err := c.listAll(ctx, RecordListOpts{ZoneID: zone.ID})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	result, err := CheckCAA(zone, records, req)
+	return result, resp, err
+}
+
+// CheckCAA checks if the CA may issue the certificate of the request with
+// the CAA records of the zone. As CAs do, it looks up the CAA records of the
+// name, following CNAME records, and walks up the tree to the apex until a
+// name has CAA records, see RFC 8659 section 3. Names without any records
+// are covered by the wildcard records of their closest existing ancestor,
+// see RFC 4592. Without CAA records any CA may issue.
+//
+// Only the records of the zone are known, names or aliases outside of the
+// zone return an error.
+func CheckCAA(zone *Zone, records []*Record, req CAAIssuance) (*CAAResult, error) {
+	name := ToRelative(zone.Name, req.Name)
+	if strings.HasSuffix(name, ".") {
+		return nil, fmt.Errorf("caa: %s is not in the zone %s", req.Name, zone.Name)
+	}
+	if IsWildcard(name) {
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "*"), ".")
+		if name == "" {
+			name = "@"
+		}
+	}
+
+	z := caaZone{
+		zone:  zone,
+		names: map[string]bool{"@": true},
+		caa:   map[string][]string{},
+		cname: map[string]string{},
+	}
+	for _, rec := range records {
+		key := FoldName(ToRelative(zone.Name, rec.Name))
+		switch rec.Type {
+		case RecordTypeCAA:
+			z.caa[key] = append(z.caa[key], rec.Value)
+		case RecordTypeCNAME:
+			z.cname[key] = rec.Value
+		}
+
+		// The ancestors of a name exist as well, if only as empty
+		// non-terminals.
+		for ; key != "@"; key = parentName(key) {
+			z.names[key] = true
+		}
+	}
+
+	for {
+		values, err := z.lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) > 0 {
+			policy, err := ParseCAAPolicy(values...)
+			if err != nil {
+				return nil, fmt.Errorf("caa: %s: %w", name, err)
+			}
+			return &CAAResult{Allowed: policy.Allows(req), Name: name, Policy: policy}, nil
+		}
+
+		if name == "@" {
+			return &CAAResult{Allowed: true}, nil
+		}
+		name = parentName(name)
+	}
+}
+
+// parentName returns the parent of a name relative to the zone, "@" for the
+// names directly below the apex.
+func parentName(name string) string {
+	if i := strings.Index(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+
+	return "@"
+}
+
+// caaZone holds the records of a zone needed to look up CAA records.
+type caaZone struct {
+	zone *Zone
+	// names holds the existing names, including empty non-terminals.
+	names map[string]bool
+	caa   map[string][]string
+	cname map[string]string
+}
+
+// owner returns the name whose records answer a query for key: key itself
+// when it exists, otherwise the wildcard of its closest existing ancestor,
+// or an empty string when neither exists.
+func (z caaZone) owner(key string) string {
+	if z.names[key] {
+		return key
+	}
+
+	encloser := parentName(key)
+	for !z.names[encloser] {
+		encloser = parentName(encloser)
+	}
+
+	wildcard := "*." + encloser
+	if encloser == "@" {
+		wildcard = "*"
+	}
+	if z.names[wildcard] {
+		return wildcard
+	}
+
+	return ""
+}
+
+// lookup returns the CAA values of the name, following CNAME records within
+// the zone.
+func (z caaZone) lookup(name string) ([]string, error) {
+	key := FoldName(name)
+	for i := 0; i <= maxCAAAliases; i++ {
+		owner := z.owner(key)
+		if owner == "" {
+			return nil, nil
+		}
+
+		target, ok := z.cname[owner]
+		if !ok {
+			return z.caa[owner], nil
+		}
+
+		rel := ToRelative(z.zone.Name, target)
+		if strings.HasSuffix(rel, ".") {
+			return nil, fmt.Errorf("caa: %s is an alias of %s outside of the zone", name, target)
+		}
+		key = FoldName(rel)
+	}
+
+	return nil, fmt.Errorf("caa: too many aliases for %s", name)
+}
@@ -0,0 +1,199 @@
+package dns
+
+import "testing"
+
+func TestCAAPolicy(t *testing.T) {
+	as := newAssert(t)
+
+	policy, err := ParseCAAPolicy(
+		`0 issue "letsencrypt.org; accounturi=https://acme-v02.api.letsencrypt.org/acme/acct/1; validationmethods=dns-01,http-01"`,
+		`128 issuewild ";"`,
+		`0 iodef "mailto:security@hetzner.com"`,
+		`0 issuemail "hetzner.com"`,
+	)
+	if !as.NoError(err) {
+		return
+	}
+	as.NoError(policy.Validate())
+	as.EqInt(1, len(policy.Issue))
+	as.EqStr("letsencrypt.org", policy.Issue[0].Domain)
+	as.EqStr("https://acme-v02.api.letsencrypt.org/acme/acct/1", policy.Issue[0].AccountURI)
+	as.EqInt(2, len(policy.Issue[0].ValidationMethods))
+	as.EqInt(1, len(policy.IssueWild))
+	as.EqStr("", policy.IssueWild[0].Domain)
+	if !policy.IssueWild[0].Critical {
+		t.Error("expected critical issuewild")
+	}
+
+	zone := &Zone{ID: "1", Name: "hetzner.com"}
+	opts, err := policy.RecordCreateOpts(zone, "@")
+	if as.NoError(err) && as.EqInt(4, len(opts)) {
+		as.EqStr(`0 issue "letsencrypt.org; accounturi=https://acme-v02.api.letsencrypt.org/acme/acct/1; validationmethods=dns-01,http-01"`, opts[0].Value)
+		as.EqStr(`128 issuewild ";"`, opts[1].Value)
+		as.EqStr(`0 iodef "mailto:security@hetzner.com"`, opts[2].Value)
+		as.EqStr(`0 issuemail "hetzner.com"`, opts[3].Value)
+		for _, opt := range opts {
+			as.EqStr(string(RecordTypeCAA), string(opt.Type))
+			as.NoError(opt.validate())
+		}
+	}
+
+	for _, value := range []string{
+		`0 issue`,
+		`256 issue "letsencrypt.org"`,
+		`0 is-sue "letsencrypt.org"`,
+		`0 issue "letsencrypt.org`,
+		`0 issue "letsencrypt.org; accounturi"`,
+		`0 issue "lets encrypt.org"`,
+	} {
+		if _, err := ParseCAAPolicy(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+
+	invalid := &CAAPolicy{IODEF: []string{"ftp://hetzner.com"}}
+	_, err = invalid.RecordCreateOpts(zone, "@")
+	as.Error(err)
+	as.NoError((&CAAPolicy{IODEF: []string{"http://hetzner.com/caa"}}).Validate())
+	invalid = &CAAPolicy{Issue: []*CAAIssuer{{Domain: "letsencrypt.org", ValidationMethods: []string{"DNS 01"}}}}
+	as.Error(invalid.Validate())
+}
+
+func TestCAAPolicyAllows(t *testing.T) {
+	policy := &CAAPolicy{
+		Issue: []*CAAIssuer{
+			{Domain: "letsencrypt.org", AccountURI: "https://acme.example/acct/1"},
+			{Domain: "sectigo.com", ValidationMethods: []string{"dns-01"}},
+		},
+		IssueWild: []*CAAIssuer{{Domain: "sectigo.com"}},
+	}
+
+	tests := map[string]struct {
+		req     CAAIssuance
+		allowed bool
+	}{
+		"account":           {CAAIssuance{Name: "www", CA: "letsencrypt.org", AccountURI: "https://acme.example/acct/1"}, true},
+		"other account":     {CAAIssuance{Name: "www", CA: "letsencrypt.org", AccountURI: "https://acme.example/acct/2"}, false},
+		"no account":        {CAAIssuance{Name: "www", CA: "LetsEncrypt.org."}, false},
+		"method":            {CAAIssuance{Name: "www", CA: "sectigo.com", ValidationMethod: "dns-01"}, true},
+		"other method":      {CAAIssuance{Name: "www", CA: "sectigo.com", ValidationMethod: "http-01"}, false},
+		"other ca":          {CAAIssuance{Name: "www", CA: "digicert.com"}, false},
+		"wildcard":          {CAAIssuance{Name: "*.www", CA: "sectigo.com", ValidationMethod: "http-01"}, true},
+		"wildcard other ca": {CAAIssuance{Name: "*.www", CA: "letsencrypt.org", AccountURI: "https://acme.example/acct/1"}, false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if allowed := policy.Allows(tt.req); allowed != tt.allowed {
+				t.Errorf("expected allowed %v but got %v", tt.allowed, allowed)
+			}
+		})
+	}
+
+	if !(&CAAPolicy{IODEF: []string{"mailto:security@hetzner.com"}}).Allows(CAAIssuance{CA: "digicert.com"}) {
+		t.Error("expected any ca without issuers")
+	}
+	critical := &CAAPolicy{Other: []*CAAProperty{{Flags: CAAFlagCritical, Tag: "future", Value: "x"}}}
+	if critical.Allows(CAAIssuance{CA: "digicert.com"}) {
+		t.Error("expected no ca with unknown critical property")
+	}
+}
+
+func TestCheckCAA(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	zone := &Zone{ID: zoneID, Name: "hetzner.com"}
+
+	api.AddRecord(zoneID, "@", RecordTypeCAA, `0 issue "letsencrypt.org"`, 3600)
+	api.AddRecord(zoneID, "shop", RecordTypeCAA, `0 issue "sectigo.com"`, 3600)
+	api.AddRecord(zoneID, "www", RecordTypeCNAME, "shop", 3600)
+	api.AddRecord(zoneID, "cdn", RecordTypeCNAME, "cdn.example.net.", 3600)
+	api.AddRecord(zoneID, "api", RecordTypeA, "192.0.2.1", 3600)
+
+	tests := map[string]struct {
+		name    string
+		ca      string
+		allowed bool
+		found   string
+	}{
+		"apex":                   {"hetzner.com", "letsencrypt.org", true, "@"},
+		"parent":                 {"v1.api", "letsencrypt.org", true, "@"},
+		"parent other ca":        {"api.hetzner.com.", "sectigo.com", false, "@"},
+		"own records":            {"shop", "sectigo.com", true, "shop"},
+		"own records other ca":   {"shop", "letsencrypt.org", false, "shop"},
+		"below own records":      {"*.shop", "sectigo.com", true, "shop"},
+		"alias":                  {"www", "sectigo.com", true, "www"},
+		"wildcard of the parent": {"*", "letsencrypt.org", true, "@"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, _, err := env.Client.Record.CheckCAA(env.Context, zone, CAAIssuance{Name: tt.name, CA: tt.ca})
+			if !as.NoError(err) {
+				return
+			}
+			if result.Allowed != tt.allowed {
+				t.Errorf("expected allowed %v but got %v", tt.allowed, result.Allowed)
+			}
+			as.EqStr(tt.found, result.Name)
+			as.NotNil(result.Policy)
+		})
+	}
+
+	_, _, err := env.Client.Record.CheckCAA(env.Context, zone, CAAIssuance{Name: "cdn", CA: "letsencrypt.org"})
+	as.Error(err)
+	_, _, err = env.Client.Record.CheckCAA(env.Context, zone, CAAIssuance{Name: "hetzner.de.", CA: "letsencrypt.org"})
+	as.Error(err)
+
+	result, err := CheckCAA(zone, nil, CAAIssuance{Name: "www", CA: "digicert.com"})
+	if as.NoError(err) {
+		if !result.Allowed || result.Name != "" || result.Policy != nil {
+			t.Errorf("expected any ca without records but got %+v", result)
+		}
+	}
+}
+
+func TestCheckCAAWildcard(t *testing.T) {
+	as := newAssert(t)
+	zone := &Zone{ID: "zone", Name: "hetzner.com"}
+	records := []*Record{
+		{Name: "@", Type: RecordTypeCAA, Value: `0 issue "letsencrypt.org"`},
+		{Name: "*.dev", Type: RecordTypeCAA, Value: `0 issue "sectigo.com"`},
+		{Name: "mail.dev", Type: RecordTypeA, Value: "192.0.2.1"},
+		{Name: "*.api", Type: RecordTypeCNAME, Value: "*.dev"},
+		{Name: "v1.api", Type: RecordTypeA, Value: "192.0.2.2"},
+	}
+
+	tests := map[string]struct {
+		name    string
+		ca      string
+		allowed bool
+		found   string
+	}{
+		"wildcard":                {"shop.dev", "sectigo.com", true, "shop.dev"},
+		"wildcard other ca":       {"shop.dev", "letsencrypt.org", false, "shop.dev"},
+		"below wildcard":          {"eu.shop.dev", "sectigo.com", true, "eu.shop.dev"},
+		"own records":             {"mail.dev", "letsencrypt.org", true, "@"},
+		"below own records":       {"eu.mail.dev", "letsencrypt.org", true, "@"},
+		"empty non-terminal":      {"dev", "letsencrypt.org", true, "@"},
+		"wildcard alias":          {"shop.api", "sectigo.com", true, "shop.api"},
+		"own records next to one": {"v1.api", "letsencrypt.org", true, "@"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := CheckCAA(zone, records, CAAIssuance{Name: tt.name, CA: tt.ca})
+			if !as.NoError(err) {
+				return
+			}
+			if result.Allowed != tt.allowed {
+				t.Errorf("expected allowed %v but got %v", tt.allowed, result.Allowed)
+			}
+			as.EqStr(tt.found, result.Name)
+		})
+	}
+}
@@ -0,0 +1,505 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the time entries of a cache are valid by default.
+const DefaultCacheTTL = time.Minute
+
+// CacheStore stores the encoded entries of a Cache.
+type CacheStore interface {
+	// Get returns the value of the key and the time it expires.
+	Get(key string) (value []byte, expires time.Time, ok bool)
+	// Set stores the value of the key.
+	Set(key string, value []byte, expires time.Time) error
+	// DeletePrefix deletes the entries with keys starting with prefix.
+	DeletePrefix(prefix string) error
+}
+
+// CacheStats holds the statistics of a cache.
+type CacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Invalidations uint64
+	// Errors counts the failures of the store, which are treated as misses.
+	Errors uint64
+}
+
+// Cache caches the responses of read calls of the cached sub-clients, see
+// NewCachedZoneClient and NewCachedRecordClient. Writes through the cached
+// sub-clients invalidate the entries of the zones they touch. Sub-clients
+// sharing a cache invalidate each other's entries.
+type Cache struct {
+	store CacheStore
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	stats CacheStats
+	// generation is incremented by every invalidation, reads started before
+	// an invalidation don't fill the cache.
+	generation uint64
+}
+
+// NewCache creates a cache with entries valid for ttl. A nil store uses a
+// MemoryCacheStore, a ttl of zero or below uses DefaultCacheTTL.
+func NewCache(store CacheStore, ttl time.Duration) *Cache {
+	if store == nil {
+		store = NewMemoryCacheStore()
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &Cache{store: store, ttl: ttl}
+}
+
+// Stats returns the statistics of the cache.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+func (c *Cache) count(fn func(s *CacheStats)) {
+	c.mu.Lock()
+	fn(&c.stats)
+	c.mu.Unlock()
+}
+
+// get decodes the entry of the key into v and reports whether it was found.
+func (c *Cache) get(key string, v interface{}) bool {
+	value, expires, ok := c.store.Get(key)
+	if !ok || !time.Now().Before(expires) || json.Unmarshal(value, v) != nil {
+		c.count(func(s *CacheStats) { s.Misses++ })
+		return false
+	}
+
+	c.count(func(s *CacheStats) { s.Hits++ })
+	return true
+}
+
+// currentGeneration returns the generation to pass to set once the value
+// has been read.
+func (c *Cache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// set stores v as the entry of the key unless the cache was invalidated
+// since generation, in which case v may be stale. The store is written
+// without holding the lock of the cache, an entry stored while the cache is
+// invalidated is therefore removed again.
+func (c *Cache) set(key string, v interface{}, generation uint64) {
+	if c.currentGeneration() != generation {
+		return
+	}
+
+	value, err := json.Marshal(v)
+	if err == nil {
+		err = c.store.Set(key, value, time.Now().Add(c.ttl))
+	}
+	if err == nil && c.currentGeneration() != generation {
+		err = c.store.DeletePrefix(key)
+	}
+	if err != nil {
+		c.count(func(s *CacheStats) { s.Errors++ })
+	}
+}
+
+func (c *Cache) deletePrefix(prefixes ...string) {
+	c.mu.Lock()
+	c.stats.Invalidations++
+	c.generation++
+	c.mu.Unlock()
+
+	for _, prefix := range prefixes {
+		if err := c.store.DeletePrefix(prefix); err != nil {
+			c.count(func(s *CacheStats) { s.Errors++ })
+		}
+	}
+}
+
+// InvalidateZone removes the cached entries of the zone, including its
+// records and the zone and record lists.
+func (c *Cache) InvalidateZone(zoneID string) {
+	c.deletePrefix(
+		"zone/"+zoneID+"/",
+		"records/"+zoneID+"?",
+		"records/?",
+		"record/",
+		"zones?",
+	)
+}
+
+// Clear removes all entries of the cache.
+func (c *Cache) Clear() {
+	c.deletePrefix("")
+}
+
+// invalidateZones invalidates the given zones, or the whole cache when a
+// zone is unknown.
+func (c *Cache) invalidateZones(zones ...*Zone) {
+	for _, zone := range zones {
+		if zone == nil || zone.ID == "" {
+			c.Clear()
+			return
+		}
+	}
+
+	for _, zone := range zones {
+		c.InvalidateZone(zone.ID)
+	}
+}
+
+// cachedList is a cached page of a list call.
+type cachedList struct {
+	Zones   []*Zone   `json:",omitempty"`
+	Records []*Record `json:",omitempty"`
+	Meta    Meta
+}
+
+// cachedResponse returns the response of a cache hit. It holds the meta data
+// of the cached response and a synthetic HTTP response with status 200.
+func cachedResponse(meta Meta) *Response {
+	return &Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       http.NoBody,
+		},
+		Meta: meta,
+	}
+}
+
+// CachedZoneClient is a ZoneClient caching zones, see Cache. On a cache hit
+// the returned Response holds the Meta of the cached response and a
+// synthetic HTTP response with status 200.
+type CachedZoneClient struct {
+	client *ZoneClient
+	cache  *Cache
+}
+
+// NewCachedZoneClient creates a cached zone client using the cache.
+func NewCachedZoneClient(client *ZoneClient, cache *Cache) *CachedZoneClient {
+	return &CachedZoneClient{client: client, cache: cache}
+}
+
+// List returns all zones with the given parameters.
+func (c *CachedZoneClient) List(ctx context.Context, opts ZoneListOpts) ([]*Zone, *Response, error) {
+	key := "zones?" + opts.values().Encode()
+
+	var list cachedList
+	if c.cache.get(key, &list) {
+		return list.Zones, cachedResponse(list.Meta), nil
+	}
+
+	generation := c.cache.currentGeneration()
+	zones, resp, err := c.client.List(ctx, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+	c.cache.set(key, cachedList{Zones: zones, Meta: resp.Meta}, generation)
+
+	return zones, resp, nil
+}
+
+// GetByID returns the zone with the given id.
+func (c *CachedZoneClient) GetByID(ctx context.Context, id string) (*Zone, *Response, error) {
+	key := "zone/" + id + "/"
+
+	var zone *Zone
+	if c.cache.get(key, &zone) && zone != nil {
+		return zone, cachedResponse(Meta{}), nil
+	}
+
+	generation := c.cache.currentGeneration()
+	zone, resp, err := c.client.GetByID(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+	c.cache.set(key, zone, generation)
+
+	return zone, resp, nil
+}
+
+// Create creates a new zone.
+func (c *CachedZoneClient) Create(ctx context.Context, opts ZoneCreateOpts) (*Zone, *Response, error) {
+	zone, resp, err := c.client.Create(ctx, opts)
+	c.cache.deletePrefix("zones?")
+	return zone, resp, err
+}
+
+// Update updates a zone.
+func (c *CachedZoneClient) Update(ctx context.Context, zone *Zone, opts ZoneUpdateOpts) (*Zone, *Response, error) {
+	defer c.cache.invalidateZones(zone)
+	return c.client.Update(ctx, zone, opts)
+}
+
+// Delete deletes a zone.
+func (c *CachedZoneClient) Delete(ctx context.Context, zone *Zone) (*Response, error) {
+	defer c.cache.invalidateZones(zone)
+	return c.client.Delete(ctx, zone)
+}
+
+// Import imports a zone file in text/plain format.
+func (c *CachedZoneClient) Import(ctx context.Context, zone *Zone, file io.Reader) (*Zone, *Response, error) {
+	defer c.cache.invalidateZones(zone)
+	return c.client.Import(ctx, zone, file)
+}
+
+// Export exports a zone in text/plain format. Exports are not cached.
+func (c *CachedZoneClient) Export(ctx context.Context, zone *Zone) (io.Reader, *Response, error) {
+	return c.client.Export(ctx, zone)
+}
+
+// ValidateFile validates a zone file.
+func (c *CachedZoneClient) ValidateFile(ctx context.Context, file io.Reader) (*ValidatedZoneFile, *Response, error) {
+	return c.client.ValidateFile(ctx, file)
+}
+
+// CachedRecordClient is a RecordClient caching records, see Cache. On a
+// cache hit the returned Response holds the Meta of the cached response and
+// a synthetic HTTP response with status 200.
+type CachedRecordClient struct {
+	client *RecordClient
+	cache  *Cache
+}
+
+// NewCachedRecordClient creates a cached record client using the cache.
+func NewCachedRecordClient(client *RecordClient, cache *Cache) *CachedRecordClient {
+	return &CachedRecordClient{client: client, cache: cache}
+}
+
+// List returns all records with the given parameters.
+func (c *CachedRecordClient) List(ctx context.Context, opts RecordListOpts) ([]*Record, *Response, error) {
+	key := "records/" + opts.ZoneID + "?" + opts.values().Encode()
+
+	var list cachedList
+	if c.cache.get(key, &list) {
+		return list.Records, cachedResponse(list.Meta), nil
+	}
+
+	generation := c.cache.currentGeneration()
+	records, resp, err := c.client.List(ctx, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+	c.cache.set(key, cachedList{Records: records, Meta: resp.Meta}, generation)
+
+	return records, resp, nil
+}
+
+// GetByID returns a record with the given id.
+func (c *CachedRecordClient) GetByID(ctx context.Context, id string) (*Record, *Response, error) {
+	key := "record/" + id + "/"
+
+	var rec *Record
+	if c.cache.get(key, &rec) && rec != nil {
+		return rec, cachedResponse(Meta{}), nil
+	}
+
+	generation := c.cache.currentGeneration()
+	rec, resp, err := c.client.GetByID(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+	c.cache.set(key, rec, generation)
+
+	return rec, resp, nil
+}
+
+// Create creates a new record.
+func (c *CachedRecordClient) Create(ctx context.Context, opts RecordCreateOpts) (*Record, *Response, error) {
+	defer c.cache.invalidateZones(opts.Zone)
+	return c.client.Create(ctx, opts)
+}
+
+// Update updates a record.
+func (c *CachedRecordClient) Update(ctx context.Context, rec *Record, opts RecordUpdateOpts) (*Record, *Response, error) {
+	defer c.cache.invalidateZones(rec.Zone, opts.Zone)
+	return c.client.Update(ctx, rec, opts)
+}
+
+// Delete deletes a record.
+func (c *CachedRecordClient) Delete(ctx context.Context, rec *Record) (*Response, error) {
+	defer c.cache.invalidateZones(rec.Zone)
+	return c.client.Delete(ctx, rec)
+}
+
+// BulkCreate creates multiple records.
+func (c *CachedRecordClient) BulkCreate(ctx context.Context, bulkOpts []RecordCreateOpts) (*RecordBulkCreateResponse, *Response, error) {
+	zones := make([]*Zone, len(bulkOpts))
+	for i, opts := range bulkOpts {
+		zones[i] = opts.Zone
+	}
+
+	defer c.cache.invalidateZones(zones...)
+	return c.client.BulkCreate(ctx, bulkOpts)
+}
+
+// BulkUpdate updates multiple records.
+func (c *CachedRecordClient) BulkUpdate(ctx context.Context, bulkOpts []RecordBulkUpdateOpts) (*RecordBulkUpdateResponse, *Response, error) {
+	zones := make([]*Zone, len(bulkOpts))
+	for i, opts := range bulkOpts {
+		zones[i] = opts.Zone
+	}
+
+	defer c.cache.invalidateZones(zones...)
+	return c.client.BulkUpdate(ctx, bulkOpts)
+}
+
+// cacheEntry is an entry of a cache store.
+type cacheEntry struct {
+	Value   []byte
+	Expires time.Time
+}
+
+// MemoryCacheStore is a CacheStore keeping its entries in memory.
+type MemoryCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewMemoryCacheStore creates an empty memory cache store.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{entries: map[string]cacheEntry{}}
+}
+
+// Get returns the value of the key and the time it expires.
+func (s *MemoryCacheStore) Get(key string) ([]byte, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	return entry.Value, entry.Expires, ok
+}
+
+// Set stores the value of the key.
+func (s *MemoryCacheStore) Set(key string, value []byte, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = cacheEntry{Value: value, Expires: expires}
+	return nil
+}
+
+// DeletePrefix deletes the entries with keys starting with prefix.
+func (s *MemoryCacheStore) DeletePrefix(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// FileCacheStore is a CacheStore keeping its entries in memory and in a
+// local JSON file, to reuse them across runs. Expired entries are dropped
+// when the file is written.
+type FileCacheStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewFileCacheStore creates a cache store backed by the file at path,
+// loading its entries if the file exists.
+func NewFileCacheStore(path string) (*FileCacheStore, error) {
+	s := &FileCacheStore{path: path, entries: map[string]cacheEntry{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("cache file %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Get returns the value of the key and the time it expires.
+func (s *FileCacheStore) Get(key string) ([]byte, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	return entry.Value, entry.Expires, ok
+}
+
+// Set stores the value of the key.
+func (s *FileCacheStore) Set(key string, value []byte, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = cacheEntry{Value: value, Expires: expires}
+	return s.write()
+}
+
+// DeletePrefix deletes the entries with keys starting with prefix.
+func (s *FileCacheStore) DeletePrefix(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return s.write()
+}
+
+// write replaces the file with the unexpired entries.
+func (s *FileCacheStore) write() error {
+	now := time.Now()
+	for key, entry := range s.entries {
+		if !now.Before(entry.Expires) {
+			delete(s.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,220 @@
+package dns
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+func TestCachedClients(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	recID := api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 0)
+
+	cache := NewCache(nil, time.Minute)
+	zones := NewCachedZoneClient(env.Client.Zone, cache)
+	records := NewCachedRecordClient(env.Client.Record, cache)
+
+	for i := 0; i < 3; i++ {
+		zone, resp, err := zones.GetByID(env.Context, zoneID)
+		if as.NoError(err) {
+			as.EqStr("hetzner.com", zone.Name)
+			as.EqInt(200, resp.StatusCode)
+		}
+
+		list, resp, err := records.List(env.Context, RecordListOpts{ZoneID: zoneID})
+		if as.NoError(err) && as.EqInt(1, len(list)) {
+			as.EqStr(recID, list[0].ID)
+			as.EqStr(zoneID, list[0].Zone.ID)
+			if resp.HasNextPage() {
+				t.Error("unexpected next page")
+			}
+		}
+	}
+	as.EqInt(1, api.Calls["GET /zones/"+zoneID])
+	as.EqInt(1, api.Calls["GET /records"])
+
+	stats := cache.Stats()
+	as.EqInt(4, int(stats.Hits))
+	as.EqInt(2, int(stats.Misses))
+
+	// A record write invalidates the records and the zone.
+	_, _, err := records.Create(env.Context, RecordCreateOpts{
+		Name: "mail", Type: RecordTypeA, Value: "192.0.2.2", Zone: &Zone{ID: zoneID},
+	})
+	if !as.NoError(err) {
+		return
+	}
+
+	list, _, err := records.List(env.Context, RecordListOpts{ZoneID: zoneID})
+	if as.NoError(err) {
+		as.EqInt(2, len(list))
+	}
+	_, _, err = zones.GetByID(env.Context, zoneID)
+	as.NoError(err)
+	as.EqInt(2, api.Calls["GET /zones/"+zoneID])
+	as.EqInt(2, api.Calls["GET /records"])
+
+	rec, _, err := records.GetByID(env.Context, recID)
+	if !as.NoError(err) {
+		return
+	}
+	_, err = records.Delete(env.Context, rec)
+	as.NoError(err)
+	list, _, err = records.List(env.Context, RecordListOpts{ZoneID: zoneID})
+	if as.NoError(err) {
+		as.EqInt(1, len(list))
+	}
+
+	// Zone lists are invalidated by zone creation.
+	_, _, err = zones.List(env.Context, ZoneListOpts{})
+	as.NoError(err)
+	_, _, err = zones.Create(env.Context, ZoneCreateOpts{Name: "hetzner.de"})
+	as.NoError(err)
+	all, _, err := zones.List(env.Context, ZoneListOpts{})
+	if as.NoError(err) {
+		as.EqInt(2, len(all))
+	}
+}
+
+func TestCacheRefusesStaleFill(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	env.Mux.HandleFunc(pathZones+"/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			close(started)
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(schema.ZoneResponse{Zone: schema.Zone{ID: "1", Name: "hetzner.com"}}) // nolint: errcheck
+	})
+
+	cache := NewCache(nil, time.Minute)
+	zones := NewCachedZoneClient(env.Client.Zone, cache)
+
+	done := make(chan error)
+	go func() {
+		_, _, err := zones.GetByID(env.Context, "1")
+		done <- err
+	}()
+
+	// The zone is written while the read is in flight.
+	<-started
+	cache.InvalidateZone("1")
+	close(release)
+	as.NoError(<-done)
+
+	_, _, err := zones.GetByID(env.Context, "1")
+	as.NoError(err)
+	as.EqInt(2, calls)
+}
+
+// blockingCacheStore is a MemoryCacheStore whose Set blocks until released.
+type blockingCacheStore struct {
+	*MemoryCacheStore
+	setting chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingCacheStore) Set(key string, value []byte, expires time.Time) error {
+	close(s.setting)
+	<-s.release
+	return s.MemoryCacheStore.Set(key, value, expires)
+}
+
+func TestCacheSetDoesNotBlock(t *testing.T) {
+	as := newAssert(t)
+
+	store := &blockingCacheStore{
+		MemoryCacheStore: NewMemoryCacheStore(),
+		setting:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	cache := NewCache(store, time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		cache.set("zone/1/", &Zone{ID: "1"}, cache.currentGeneration())
+		close(done)
+	}()
+
+	// The cache is usable and can be invalidated while the store is written.
+	<-store.setting
+	cache.InvalidateZone("1")
+	as.EqInt(1, int(cache.Stats().Invalidations))
+	close(store.release)
+	<-done
+
+	var zone Zone
+	if cache.get("zone/1/", &zone) {
+		t.Error("expected entry stored during invalidation to be removed")
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+
+	zones := NewCachedZoneClient(env.Client.Zone, NewCache(nil, 20*time.Millisecond))
+	zones.GetByID(env.Context, zoneID) // nolint: errcheck
+	zones.GetByID(env.Context, zoneID) // nolint: errcheck
+	as.EqInt(1, api.Calls["GET /zones/"+zoneID])
+
+	time.Sleep(30 * time.Millisecond)
+	zones.GetByID(env.Context, zoneID) // nolint: errcheck
+	as.EqInt(2, api.Calls["GET /zones/"+zoneID])
+}
+
+func TestFileCacheStore(t *testing.T) {
+	as := newAssert(t)
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	store, err := NewFileCacheStore(path)
+	if !as.NoError(err) {
+		return
+	}
+
+	expires := time.Now().Add(time.Hour)
+	as.NoError(store.Set("zone/1/", []byte(`{"ID":"1"}`), expires))
+	as.NoError(store.Set("zone/2/", []byte(`{"ID":"2"}`), expires))
+	as.NoError(store.Set("expired", []byte(`{}`), time.Now().Add(-time.Second)))
+	as.NoError(store.DeletePrefix("zone/2/"))
+
+	store, err = NewFileCacheStore(path)
+	if !as.NoError(err) {
+		return
+	}
+
+	value, exp, ok := store.Get("zone/1/")
+	if !ok || !exp.Equal(expires) {
+		t.Fatalf("expected stored entry but got %v %s", ok, exp)
+	}
+	as.EqStr(`{"ID":"1"}`, string(value))
+	if _, _, ok := store.Get("zone/2/"); ok {
+		t.Error("expected deleted entry")
+	}
+	if _, _, ok := store.Get("expired"); ok {
+		t.Error("expected expired entry to be dropped")
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jobstoit/hetzner-dns-go/dns/schema"
 )
@@ -30,9 +32,20 @@ type Client struct {
 	applicationVersion string
 	userAgent          string
 
+	connectTimeout        time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+	requestTimeout        time.Duration
+	transferTimeout       time.Duration
+	rateLimiter           *rateLimiter
+	dryRun                bool
+	dryRunWriter          io.Writer
+	auditSinks            []AuditSink
+
 	Zone          *ZoneClient
 	Record        *RecordClient
 	PrimaryServer *PrimaryServerClient
+	SecondaryZone *SecondaryZoneClient
 }
 
 // ClientOption is used to configure a client.
@@ -64,6 +77,8 @@ func WithApplication(name, version string) ClientOption {
 }
 
 // WithHTTPClient configures a Client to perform HTTP requests with httpClient.
+// The connect, TLS handshake and response header timeouts are not applied to
+// a custom HTTP client.
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(client *Client) {
 		client.httpClient = httpClient
@@ -77,30 +92,119 @@ func WithDebugWriter(wr io.Writer) ClientOption {
 	}
 }
 
+// WithConnectTimeout configures the maximum time the default HTTP client
+// waits for a connection to the API to be established. A zero duration
+// disables the timeout.
+func WithConnectTimeout(d time.Duration) ClientOption {
+	return func(client *Client) {
+		client.connectTimeout = d
+	}
+}
+
+// WithTLSHandshakeTimeout configures the maximum time the default HTTP client
+// waits for the TLS handshake. A zero duration disables the timeout.
+func WithTLSHandshakeTimeout(d time.Duration) ClientOption {
+	return func(client *Client) {
+		client.tlsHandshakeTimeout = d
+	}
+}
+
+// WithResponseHeaderTimeout configures the maximum time the default HTTP
+// client waits for the response headers after the request has been written.
+// A zero duration disables the timeout.
+func WithResponseHeaderTimeout(d time.Duration) ClientOption {
+	return func(client *Client) {
+		client.responseHeaderTimeout = d
+	}
+}
+
+// WithRequestTimeout configures the timeout applied to a request when its
+// context has no deadline. The timeout starts after the rate limit, see
+// WithRateLimit, allows the request. A zero duration disables the timeout.
+func WithRequestTimeout(d time.Duration) ClientOption {
+	return func(client *Client) {
+		client.requestTimeout = d
+	}
+}
+
+// WithTransferTimeout configures the timeout applied to zone file imports and
+// exports when their context has no deadline. A zero duration disables the timeout.
+func WithTransferTimeout(d time.Duration) ClientOption {
+	return func(client *Client) {
+		client.transferTimeout = d
+	}
+}
+
+// WithRateLimit limits the requests of the client, including concurrent
+// requests, to the given number of requests per second with bursts of up to
+// burst requests. Requests wait for their turn until their context is done.
+// A rate of zero or below disables the limit.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(client *Client) {
+		client.rateLimiter = nil
+		if requestsPerSecond > 0 {
+			client.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+		}
+	}
+}
+
 // NewClient creates a new client.
 func NewClient(options ...ClientOption) *Client {
 	client := &Client{
-		endpoint:   Endpoint,
-		tokenValid: true,
-		httpClient: http.DefaultClient,
+		endpoint:              Endpoint,
+		tokenValid:            true,
+		connectTimeout:        DefaultConnectTimeout,
+		tlsHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		responseHeaderTimeout: DefaultResponseHeaderTimeout,
+		requestTimeout:        DefaultRequestTimeout,
+		transferTimeout:       DefaultTransferTimeout,
 	}
 
 	for _, option := range options {
 		option(client)
 	}
 
+	if client.httpClient == nil {
+		client.httpClient = client.defaultHTTPClient()
+	}
+
 	client.buildUserAgent()
 
 	client.Zone = &ZoneClient{client}
 	client.Record = &RecordClient{client}
 	client.PrimaryServer = &PrimaryServerClient{client}
+	client.SecondaryZone = NewSecondaryZoneClient(client.Zone, client.PrimaryServer)
 
 	return client
 }
 
+func (c *Client) defaultHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   c.connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = c.tlsHandshakeTimeout
+	transport.ResponseHeaderTimeout = c.responseHeaderTimeout
+
+	return &http.Client{Transport: transport}
+}
+
 // NewRequest creates an HTTP request against the API. The returned request
 // is assigned with ctx and has all necessary headers set (auth, user agent, etc.).
+// If ctx has no deadline the request timeout of the client is applied when
+// the request is sent with Do.
 func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	return c.newRequest(ctx, method, path, body)
+}
+
+// newTransferRequest creates a request like NewRequest but marks it to be
+// sent with the transfer timeout, used for zone file imports and exports.
+func (c *Client) newTransferRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	return c.newRequest(context.WithValue(ctx, transferKey{}, true), method, path, body)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
 	url := c.endpoint + path
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
@@ -117,10 +221,29 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Re
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+
 	req = req.WithContext(ctx)
 	return req, nil
 }
 
+// transferKey is the context key marking requests created by
+// newTransferRequest.
+type transferKey struct{}
+
+// timeout returns the timeout Do applies to r, zero when the context of r
+// already has a deadline.
+func (c *Client) timeout(r *http.Request) time.Duration {
+	ctx := r.Context()
+	if _, ok := ctx.Deadline(); ok {
+		return 0
+	}
+	if transfer, _ := ctx.Value(transferKey{}).(bool); transfer {
+		return c.transferTimeout
+	}
+
+	return c.requestTimeout
+}
+
 func (c *Client) buildUserAgent() {
 	switch {
 	case c.applicationName != "" && c.applicationVersion != "":
@@ -157,18 +280,42 @@ func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
 		fmt.Fprintf(c.debugWriter, "--- Request:\n%s\n\n", dumpReq)
 	}
 
+	if c.dryRun {
+		if path := c.apiPath(r); isMutating(r, path) {
+			return c.doDryRun(r, path, body, v)
+		}
+	}
+
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(r.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	// The timeout starts once the rate limiter granted the request, time
+	// spent waiting for a slot doesn't count against it.
+	if timeout := c.timeout(r); timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(r)
 	if err != nil {
 		return nil, err
 	}
-	response := &Response{Response: resp}
+	response := &Response{Response: resp, Attempts: 1}
 	body, err = io.ReadAll(resp.Body)
+	response.Duration = time.Since(start)
 	if err != nil {
 		resp.Body.Close()
 		return response, err
 	}
 	resp.Body.Close()
 	resp.Body = io.NopCloser(bytes.NewReader(body))
+	response.RawBody = body
+	response.readHeaders()
 
 	if c.debugWriter != nil {
 		dumpResp, err := httputil.DumpResponse(resp, true)
@@ -197,6 +344,13 @@ func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
 	return response, err
 }
 
+// log writes msg to the debug writer of the client, if any.
+func (c *Client) log(msg string) {
+	if c.debugWriter != nil {
+		fmt.Fprintf(c.debugWriter, "%s\n", msg)
+	}
+}
+
 // ListOpts specifies options for listing resources
 type ListOpts struct {
 	Page    int
@@ -218,6 +372,78 @@ func (l ListOpts) values() url.Values {
 type Response struct {
 	*http.Response
 	Meta Meta
+
+	// Duration is the time between sending the request and reading the
+	// complete response body.
+	Duration time.Duration
+	// Attempts is the number of requests sent to obtain this response.
+	Attempts int
+	// RateLimit holds the rate limit information sent by the API.
+	RateLimit RateLimit
+	// RequestID is the id the API assigned to the request, if any.
+	RequestID string
+	// RawBody is the unparsed response body.
+	RawBody []byte
+	// DryRun reports whether the response was synthesized in dry run mode
+	// instead of being sent by the API, see WithDryRun.
+	DryRun bool
+}
+
+// RateLimit represents the rate limit headers of an API response.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// HasNextPage reports whether there are more pages after the page of this response.
+func (r *Response) HasNextPage() bool {
+	return r != nil && r.Meta.Pagination != nil && r.Meta.Pagination.Page < r.Meta.Pagination.LastPage
+}
+
+// NextPage returns the number of the page following this response or 0 when
+// this response contains the last page.
+func (r *Response) NextPage() int {
+	if !r.HasNextPage() {
+		return 0
+	}
+
+	return r.Meta.Pagination.Page + 1
+}
+
+func (r *Response) readHeaders() {
+	r.RateLimit.Limit = headerInt(r.Header, "RateLimit-Limit", "X-RateLimit-Limit")
+	r.RateLimit.Remaining = headerInt(r.Header, "RateLimit-Remaining", "X-RateLimit-Remaining")
+
+	// The reset header either holds the seconds until the reset or a unix timestamp.
+	if reset := headerInt(r.Header, "RateLimit-Reset", "X-RateLimit-Reset"); reset > 0 {
+		if reset > 1e9 {
+			r.RateLimit.Reset = time.Unix(int64(reset), 0)
+		} else {
+			r.RateLimit.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	for _, key := range []string{"X-Request-Id", "X-Kong-Request-Id", "X-Correlation-Id"} {
+		if id := r.Header.Get(key); id != "" {
+			r.RequestID = id
+			break
+		}
+	}
+}
+
+// headerInt returns the integer value of the first of the given headers set.
+func headerInt(header http.Header, keys ...string) int {
+	for _, key := range keys {
+		if v := header.Get(key); v != "" {
+			i, err := strconv.Atoi(v)
+			if err == nil {
+				return i
+			}
+		}
+	}
+
+	return 0
 }
 
 func (r *Response) readMeta(body []byte) error {
@@ -2,11 +2,13 @@ package dns
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 type testEnv struct {
@@ -96,3 +98,109 @@ func TestClientDo(t *testing.T) {
 		t.Fatalf("unexpected callCount: %v", callCount)
 	}
 }
+
+func TestClientRequestTimeout(t *testing.T) {
+	client := NewClient(WithRequestTimeout(time.Minute), WithTransferTimeout(time.Hour))
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := req.Context().Deadline(); ok {
+		t.Error("unexpected deadline before the request is sent")
+	}
+	if d := client.timeout(req); d != time.Minute {
+		t.Errorf("unexpected request timeout: %v", d)
+	}
+
+	req, err = client.newTransferRequest(context.Background(), http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := client.timeout(req); d != time.Hour {
+		t.Errorf("unexpected transfer timeout: %v", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req, err = client.NewRequest(ctx, http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := client.timeout(req); d != 0 {
+		t.Errorf("existing deadline overwritten: %v", d)
+	}
+}
+
+func TestClientDoTimeout(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	env.Mux.HandleFunc("/hang", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	})
+
+	env.Client = NewClient(
+		WithEndpoint(env.Server.URL),
+		WithRequestTimeout(50*time.Millisecond),
+	)
+
+	req, _ := env.Client.NewRequest(env.Context, http.MethodGet, "/hang", nil)
+	if _, err := env.Client.Do(req, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded but got: %v", err)
+	}
+}
+
+func TestClientResponseMetadata(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	env.Mux.HandleFunc("/meta", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("RateLimit-Limit", "300")
+		w.Header().Set("RateLimit-Remaining", "299")
+		w.Header().Set("RateLimit-Reset", "60")
+		w.Header().Set("X-Request-Id", "abc123")
+		fmt.Fprint(w, `{"meta":{"pagination":{"page":1,"per_page":1,"last_page":2,"total_entries":2}}}`)
+	})
+
+	req, _ := env.Client.NewRequest(env.Context, http.MethodGet, "/meta", nil)
+	resp, err := env.Client.Do(req, nil)
+	if !as.NoError(err) {
+		return
+	}
+
+	as.EqInt(1, resp.Attempts)
+	as.EqInt(300, resp.RateLimit.Limit)
+	as.EqInt(299, resp.RateLimit.Remaining)
+	as.EqStr("abc123", resp.RequestID)
+	if resp.RateLimit.Reset.Before(time.Now()) {
+		t.Errorf("unexpected rate limit reset: %v", resp.RateLimit.Reset)
+	}
+	if resp.Duration <= 0 {
+		t.Errorf("expected request duration but got %v", resp.Duration)
+	}
+	if !strings.Contains(string(resp.RawBody), "pagination") {
+		t.Errorf("unexpected raw body: %s", resp.RawBody)
+	}
+
+	if !resp.HasNextPage() {
+		t.Error("expected next page")
+	}
+	as.EqInt(2, resp.NextPage())
+
+	resp.Meta.Pagination.Page = 2
+	if resp.HasNextPage() {
+		t.Error("unexpected next page")
+	}
+	as.EqInt(0, resp.NextPage())
+}
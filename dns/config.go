@@ -0,0 +1,554 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigVersion is the version of the declarative config format written and
+// understood by this package.
+const ConfigVersion = 1
+
+// Config is the declarative description of zones and their records. It is
+// stored as YAML or JSON, e.g.:
+//
+//	version: 1
+//	include:
+//	  - zones/*.yaml
+//	zones:
+//	  - name: example.com
+//	    ttl: 3600
+//	    records:
+//	      - name: "@"
+//	        type: MX
+//	        values:
+//	          - preference: 10
+//	            exchange: mail.example.com.
+//	      - name: www
+//	        type: A
+//	        ttl: 300
+//	        values: [192.0.2.1, 192.0.2.2]
+//
+// YAML anchors, aliases and merge keys can be used to share record sets.
+type Config struct {
+	Version int           `yaml:"version" json:"version"`
+	Include []string      `yaml:"include,omitempty" json:"include,omitempty"`
+	Zones   []*ZoneConfig `yaml:"zones" json:"zones"`
+}
+
+// ZoneConfig is the declarative description of a zone.
+type ZoneConfig struct {
+	Name string `yaml:"name" json:"name"`
+	// Ttl is the default ttl of the records in the zone.
+	Ttl            int                   `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+	PrimaryServers []PrimaryServerConfig `yaml:"primary_servers,omitempty" json:"primary_servers,omitempty"`
+	Records        []*RecordSetConfig    `yaml:"records,omitempty" json:"records,omitempty"`
+}
+
+// PrimaryServerConfig is the declarative description of a primary server.
+type PrimaryServerConfig struct {
+	Address string `yaml:"address" json:"address"`
+	Port    int    `yaml:"port,omitempty" json:"port,omitempty"`
+}
+
+// RecordSetConfig is the declarative description of all records of a type
+// with the same name.
+type RecordSetConfig struct {
+	Name   string        `yaml:"name" json:"name"`
+	Type   RecordType    `yaml:"type" json:"type"`
+	Ttl    *int          `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+	Values []RecordValue `yaml:"values" json:"values"`
+}
+
+// RecordValue is a single value of a record set. It is written as a plain
+// string in zone file format or, for MX, SRV and CAA records and values with
+// their own ttl, as a mapping of its fields.
+type RecordValue struct {
+	Value string `yaml:"value,omitempty" json:"value,omitempty"`
+	// Ttl overrides the ttl of the record set for this value.
+	Ttl *int `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+
+	// MX fields.
+	Preference *int   `yaml:"preference,omitempty" json:"preference,omitempty"`
+	Exchange   string `yaml:"exchange,omitempty" json:"exchange,omitempty"`
+
+	// SRV fields.
+	Priority *int   `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Weight   *int   `yaml:"weight,omitempty" json:"weight,omitempty"`
+	Port     *int   `yaml:"port,omitempty" json:"port,omitempty"`
+	Target   string `yaml:"target,omitempty" json:"target,omitempty"`
+
+	// CAA fields, the CAA value is held by Value.
+	Flags *int   `yaml:"flags,omitempty" json:"flags,omitempty"`
+	Tag   string `yaml:"tag,omitempty" json:"tag,omitempty"`
+}
+
+// recordValueFields is used to (un)marshal the structured form of a RecordValue.
+type recordValueFields RecordValue
+
+var recordValueKeys = map[string]bool{
+	"value": true, "ttl": true, "preference": true, "exchange": true, "priority": true,
+	"weight": true, "port": true, "target": true, "flags": true, "tag": true,
+}
+
+func (v RecordValue) structured() bool {
+	return v.Preference != nil || v.Exchange != "" || v.Priority != nil || v.Weight != nil ||
+		v.Port != nil || v.Target != "" || v.Flags != nil || v.Tag != ""
+}
+
+// UnmarshalYAML is an implementation of yaml.Unmarshaler.
+func (v *RecordValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = RecordValue{Value: node.Value}
+		return nil
+	}
+
+	var keys map[string]interface{}
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		if !recordValueKeys[key] {
+			return fmt.Errorf("line %d: unknown record value field %q", node.Line, key)
+		}
+	}
+
+	return node.Decode((*recordValueFields)(v))
+}
+
+// MarshalYAML is an implementation of yaml.Marshaler.
+func (v RecordValue) MarshalYAML() (interface{}, error) {
+	if !v.structured() && v.Ttl == nil {
+		return v.Value, nil
+	}
+	return recordValueFields(v), nil
+}
+
+// UnmarshalJSON is an implementation of encoding/json.Unmarshaler.
+func (v *RecordValue) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*v = RecordValue{Value: s}
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*recordValueFields)(v))
+}
+
+// MarshalJSON is an implementation of encoding/json.Marshaler.
+func (v RecordValue) MarshalJSON() ([]byte, error) {
+	if !v.structured() && v.Ttl == nil {
+		return json.Marshal(v.Value)
+	}
+	return json.Marshal(recordValueFields(v))
+}
+
+// Render returns the value in zone file format as used by the API for a
+// record of the given type.
+func (v RecordValue) Render(typ RecordType) (string, error) {
+	if !v.structured() {
+		if v.Value == "" {
+			return "", errors.New("value required")
+		}
+		return v.Value, nil
+	}
+
+	switch typ {
+	case RecordTypeMX:
+		if v.Preference == nil || v.Exchange == "" || v.Value != "" {
+			return "", errors.New("mx value requires preference and exchange")
+		}
+		return fmt.Sprintf("%d %s", *v.Preference, v.Exchange), nil
+	case RecordTypeSRV:
+		if v.Priority == nil || v.Weight == nil || v.Port == nil || v.Target == "" || v.Value != "" {
+			return "", errors.New("srv value requires priority, weight, port and target")
+		}
+		return fmt.Sprintf("%d %d %d %s", *v.Priority, *v.Weight, *v.Port, v.Target), nil
+	case RecordTypeCAA:
+		if v.Tag == "" {
+			return "", errors.New("caa value requires tag")
+		}
+		flags := 0
+		if v.Flags != nil {
+			flags = *v.Flags
+		}
+		return fmt.Sprintf("%d %s %s", flags, v.Tag, quoteTXT(v.Value)), nil
+	default:
+		return "", fmt.Errorf("structured value not supported for %s records", typ)
+	}
+}
+
+// ParseRecordValue parses a value in zone file format of a record of the
+// given type. MX, SRV and CAA values are parsed into their fields, other
+// values and values which can't be parsed are kept as plain value.
+func ParseRecordValue(typ RecordType, value string) RecordValue {
+	fields := strings.Fields(value)
+	atoi := func(s string) *int {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil
+		}
+		return &i
+	}
+
+	switch {
+	case typ == RecordTypeMX && len(fields) == 2:
+		if pref := atoi(fields[0]); pref != nil {
+			return RecordValue{Preference: pref, Exchange: fields[1]}
+		}
+	case typ == RecordTypeSRV && len(fields) == 4:
+		priority, weight, port := atoi(fields[0]), atoi(fields[1]), atoi(fields[2])
+		if priority != nil && weight != nil && port != nil {
+			return RecordValue{Priority: priority, Weight: weight, Port: port, Target: fields[3]}
+		}
+	case typ == RecordTypeCAA && len(fields) >= 3:
+		flags := atoi(fields[0])
+		rest := strings.TrimSpace(strings.TrimSpace(value)[len(fields[0]):])
+		rest = strings.TrimSpace(rest[len(fields[1]):])
+		if unquoted, remainder, ok := unquoteTXTString(rest); ok && strings.TrimSpace(remainder) == "" {
+			rest = unquoted
+		}
+		if flags != nil {
+			return RecordValue{Flags: flags, Tag: fields[1], Value: rest}
+		}
+	}
+
+	return RecordValue{Value: value}
+}
+
+// ParseConfig parses a config in YAML or JSON format and validates it.
+// Configs with includes must be loaded with LoadConfig.
+func ParseConfig(r io.Reader) (*Config, error) {
+	cfg, err := decodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Include) > 0 {
+		return nil, errors.New("config includes require LoadConfig")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadConfig reads the config file at path in YAML or JSON format, merges
+// the zones of the included files and validates the result. Includes are
+// glob patterns relative to the including file.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	if err := loadConfigFile(cfg, path, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func loadConfigFile(cfg *Config, path string, seen map[string]bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if seen[abs] {
+		return fmt.Errorf("%s: include cycle", path)
+	}
+	seen[abs] = true
+	defer delete(seen, abs)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	file, err := decodeConfig(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	switch {
+	case file.Version == 0:
+	case cfg.Version == 0:
+		cfg.Version = file.Version
+	case cfg.Version != file.Version:
+		return fmt.Errorf("%s: version %d differs from version %d", path, file.Version, cfg.Version)
+	}
+	cfg.Zones = append(cfg.Zones, file.Zones...)
+
+	for _, include := range file.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		matches, err := filepath.Glob(include)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("%s: include %s matches no files", path, include)
+		}
+
+		for _, match := range matches {
+			if err := loadConfigFile(cfg, match, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeConfig(r io.Reader) (*Config, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	cfg := &Config{}
+	if err := dec.Decode(cfg); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// WriteYAML writes the config in YAML format.
+func (c *Config) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// WriteJSON writes the config in JSON format.
+func (c *Config) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// Validate checks if the config is valid.
+func (c *Config) Validate() error {
+	if c.Version == 0 {
+		return errors.New("version required")
+	}
+	if c.Version != ConfigVersion {
+		return fmt.Errorf("unsupported version %d", c.Version)
+	}
+
+	names := map[string]bool{}
+	for i, zone := range c.Zones {
+		if zone == nil {
+			return fmt.Errorf("zones[%d]: zone required", i)
+		}
+		if err := zone.Validate(); err != nil {
+			return fmt.Errorf("zones[%d] %s: %w", i, zone.Name, err)
+		}
+
+		name := CanonicalName(zone.Name, "@")
+		if names[name] {
+			return fmt.Errorf("zones[%d]: duplicate zone %s", i, zone.Name)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
+
+// Validate checks if the zone config is valid.
+func (z *ZoneConfig) Validate() error {
+	if z.Name == "" {
+		return errors.New("name required")
+	}
+	if z.Ttl < 0 {
+		return errors.New("invalid ttl")
+	}
+
+	for i, ps := range z.PrimaryServers {
+		port := ps.Port
+		if port == 0 {
+			port = DefaultPrimaryServerPort
+		}
+		if err := validatePrimaryServerAddress(ps.Address, port); err != nil {
+			return fmt.Errorf("primary_servers[%d]: %w", i, err)
+		}
+	}
+
+	sets := map[string]bool{}
+	types := map[string][]RecordType{}
+	for i, set := range z.Records {
+		if set == nil {
+			return fmt.Errorf("records[%d]: record set required", i)
+		}
+		if err := set.validate(); err != nil {
+			return fmt.Errorf("records[%d] %s %s: %w", i, set.Name, set.Type, err)
+		}
+
+		name := CanonicalName(z.Name, set.Name)
+		key := name + " " + string(set.Type)
+		if sets[key] {
+			return fmt.Errorf("records[%d]: duplicate record set %s %s", i, set.Name, set.Type)
+		}
+		sets[key] = true
+		types[name] = append(types[name], set.Type)
+	}
+
+	for name, typs := range types {
+		for _, typ := range typs {
+			if typ == RecordTypeCNAME && len(typs) > 1 {
+				return fmt.Errorf("records: cname %s can't coexist with other records", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *RecordSetConfig) validate() error {
+	if s.Name == "" {
+		return errors.New("name required")
+	}
+	if s.Type == "" {
+		return errors.New("type required")
+	}
+	if !s.Type.valid() {
+		return fmt.Errorf("unknown type %s", s.Type)
+	}
+	if s.Type == RecordTypeSOA {
+		return errors.New("soa records are managed by the api")
+	}
+	if s.Ttl != nil && *s.Ttl < 0 {
+		return errors.New("invalid ttl")
+	}
+	if len(s.Values) == 0 {
+		return errors.New("values required")
+	}
+	if s.Type == RecordTypeCNAME && len(s.Values) > 1 {
+		return errors.New("cname requires a single value")
+	}
+
+	seen := map[string]bool{}
+	for i, v := range s.Values {
+		if v.Ttl != nil && *v.Ttl < 0 {
+			return fmt.Errorf("values[%d]: invalid ttl", i)
+		}
+
+		value, err := v.Render(s.Type)
+		if err != nil {
+			return fmt.Errorf("values[%d]: %w", i, err)
+		}
+		if seen[value] {
+			return fmt.Errorf("values[%d]: duplicate value %s", i, value)
+		}
+		seen[value] = true
+	}
+
+	return nil
+}
+
+// RecordCreateOpts returns the options to create the records of the zone
+// config in the given zone. The ttl of a value takes precedence over the ttl of
+// its set, records without ttl use the default ttl of the zone.
+func (z *ZoneConfig) RecordCreateOpts(zone *Zone) ([]RecordCreateOpts, error) {
+	var opts []RecordCreateOpts
+	for _, set := range z.Records {
+		for _, v := range set.Values {
+			value, err := v.Render(set.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", set.Name, set.Type, err)
+			}
+
+			var ttl *int
+			if v.Ttl != nil {
+				t := *v.Ttl
+				ttl = &t
+			} else if set.Ttl != nil {
+				t := *set.Ttl
+				ttl = &t
+			}
+
+			opts = append(opts, RecordCreateOpts{
+				Name:  ToRelative(z.Name, set.Name),
+				Ttl:   ttl,
+				Type:  set.Type,
+				Value: value,
+				Zone:  zone,
+			})
+		}
+	}
+
+	return opts, nil
+}
+
+// ZoneConfigFromAPI converts a zone with its records and primary servers as
+// returned by the API to a zone config. SOA records are left out as they are
+// managed by the API.
+func ZoneConfigFromAPI(zone *Zone, records []*Record, servers []*PrimaryServer) *ZoneConfig {
+	cfg := &ZoneConfig{
+		Name: zone.Name,
+		Ttl:  zone.Ttl,
+	}
+
+	for _, server := range servers {
+		cfg.PrimaryServers = append(cfg.PrimaryServers, PrimaryServerConfig{
+			Address: server.Address,
+			Port:    server.Port,
+		})
+	}
+
+	for _, rrset := range GroupRRSets(records) {
+		if rrset.Type == RecordTypeSOA {
+			continue
+		}
+
+		// Records of a set may have differing ttls. The set takes the most
+		// common ttl and the values of the other records keep their own.
+		ttls := make([]int, len(rrset.Records))
+		counts := map[int]int{}
+		setTtl := 0
+		for i, rec := range rrset.Records {
+			ttls[i] = rec.Ttl
+			if ttls[i] == 0 {
+				ttls[i] = zone.Ttl
+			}
+			counts[ttls[i]]++
+			if counts[ttls[i]] > counts[setTtl] {
+				setTtl = ttls[i]
+			}
+		}
+
+		set := &RecordSetConfig{Name: rrset.Name, Type: rrset.Type}
+		if setTtl > 0 && setTtl != zone.Ttl {
+			ttl := setTtl
+			set.Ttl = &ttl
+		}
+		for i, value := range rrset.Values {
+			v := ParseRecordValue(rrset.Type, value)
+			if ttls[i] != setTtl {
+				ttl := ttls[i]
+				v.Ttl = &ttl
+			}
+			set.Values = append(set.Values, v)
+		}
+		cfg.Records = append(cfg.Records, set)
+	}
+
+	return cfg
+}
@@ -0,0 +1,352 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	mdns "github.com/miekg/dns"
+)
+
+// ZonePlan holds the changes needed to converge a zone to its config.
+type ZonePlan struct {
+	Config *ZoneConfig
+	// Zone is the existing zone, nil when the zone has to be created.
+	Zone *Zone
+	// Ttl is set when the default ttl of an existing zone has to be updated.
+	Ttl *int
+
+	Create []RecordCreateOpts
+	Update []RecordBulkUpdateOpts
+	Delete []*Record
+
+	// DeletePrimaryServers holds the primary servers to delete when the
+	// config has no primary servers.
+	DeletePrimaryServers []*PrimaryServer
+}
+
+// Empty reports whether the plan contains no changes. Primary servers
+// configured for the zone are reconciled when the plan is applied and are
+// not reflected here.
+func (p *ZonePlan) Empty() bool {
+	return p.Zone != nil && p.Ttl == nil && len(p.Create) == 0 && len(p.Update) == 0 &&
+		len(p.Delete) == 0 && len(p.DeletePrimaryServers) == 0
+}
+
+// plannedRecord is a record of the config or the API matched by its data.
+type plannedRecord struct {
+	key      string
+	nameType string
+	ttl      int
+}
+
+func planRecord(zone string, name string, ttl int, typ RecordType, value string) (plannedRecord, error) {
+	rr, err := parseRR(zone, name, ttl, typ, value)
+	if err != nil {
+		return plannedRecord{}, err
+	}
+
+	return plannedRecord{
+		key:      rrKey(rr),
+		nameType: FoldName(rr.Header().Name) + " " + mdns.TypeToString[rr.Header().Rrtype],
+		ttl:      ttl,
+	}, nil
+}
+
+// PlanZone compares the zone config with the zone in the API and returns the
+// changes needed to converge it, see PlanZone.
+func (c *Client) PlanZone(ctx context.Context, cfg *ZoneConfig) (*ZonePlan, error) {
+	return PlanZone(ctx, c.API(), cfg)
+}
+
+// PlanZone compares the zone config with the zone in the API and returns the
+// changes needed to converge it. Records not in the config, except for the
+// SOA record, are planned for deletion.
+func PlanZone(ctx context.Context, api API, cfg *ZoneConfig) (*ZonePlan, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	plan := &ZonePlan{Config: cfg}
+
+	zones, _, err := api.Zone.List(ctx, ZoneListOpts{Name: cfg.Name})
+	if err != nil {
+		return nil, err
+	}
+	for _, zone := range zones {
+		if CanonicalName(zone.Name, "@") == CanonicalName(cfg.Name, "@") {
+			plan.Zone = zone
+		}
+	}
+
+	zone := plan.Zone
+	var records []*Record
+	if zone == nil {
+		zone = &Zone{Name: cfg.Name, Ttl: cfg.Ttl}
+	} else {
+		if cfg.Ttl > 0 && cfg.Ttl != zone.Ttl {
+			ttl := cfg.Ttl
+			plan.Ttl = &ttl
+		}
+
+		records, _, err = listAllRecords(ctx, api.Record, RecordListOpts{ZoneID: zone.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(cfg.PrimaryServers) == 0 {
+			plan.DeletePrimaryServers, _, err = api.PrimaryServer.List(ctx, PrimaryServerListOpts{ZoneID: zone.ID})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	zoneTtl := zone.Ttl
+	if cfg.Ttl > 0 {
+		zoneTtl = cfg.Ttl
+	}
+
+	desired, err := cfg.RecordCreateOpts(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredPlans := make([]plannedRecord, len(desired))
+	for i, opts := range desired {
+		ttl := zoneTtl
+		if opts.Ttl != nil {
+			ttl = *opts.Ttl
+		}
+
+		desiredPlans[i], err = planRecord(zone.Name, opts.Name, ttl, opts.Type, opts.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", opts.Name, opts.Type, err)
+		}
+	}
+
+	var existing []*Record
+	var existingPlans []plannedRecord
+	for _, rec := range records {
+		if rec.Type == RecordTypeSOA {
+			continue
+		}
+
+		ttl := rec.Ttl
+		if ttl == 0 {
+			ttl = zone.Ttl
+		}
+
+		p, err := planRecord(zone.Name, rec.Name, ttl, rec.Type, rec.Value)
+		if err != nil {
+			return nil, fmt.Errorf("record %s: %w", rec.ID, err)
+		}
+		existing = append(existing, rec)
+		existingPlans = append(existingPlans, p)
+	}
+
+	update := func(rec *Record, opts RecordCreateOpts) {
+		plan.Update = append(plan.Update, RecordBulkUpdateOpts{
+			ID:    rec.ID,
+			Type:  opts.Type,
+			Zone:  zone,
+			Name:  opts.Name,
+			Value: opts.Value,
+			Ttl:   opts.Ttl,
+		})
+	}
+
+	// Match records with the same data first, then pair the remaining
+	// records with the same name and type to update their values.
+	matchedDesired := make([]bool, len(desired))
+	matchedExisting := make([]bool, len(existing))
+	for _, sameData := range []bool{true, false} {
+		for i, d := range desiredPlans {
+			if matchedDesired[i] {
+				continue
+			}
+
+			for j, e := range existingPlans {
+				if matchedExisting[j] {
+					continue
+				}
+				if (sameData && d.key != e.key) || (!sameData && d.nameType != e.nameType) {
+					continue
+				}
+
+				matchedDesired[i] = true
+				matchedExisting[j] = true
+				if !sameData || d.ttl != e.ttl {
+					update(existing[j], desired[i])
+				}
+				break
+			}
+		}
+	}
+
+	for i, opts := range desired {
+		if !matchedDesired[i] {
+			plan.Create = append(plan.Create, opts)
+		}
+	}
+	for j, rec := range existing {
+		if !matchedExisting[j] {
+			plan.Delete = append(plan.Delete, rec)
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplyZonePlan applies the changes of the plan and returns the zone, see
+// ApplyZonePlan.
+func (c *Client) ApplyZonePlan(ctx context.Context, plan *ZonePlan) (*Zone, error) {
+	return ApplyZonePlan(ctx, c.API(), plan)
+}
+
+// ApplyZonePlan applies the changes of the plan through api, creating the
+// zone when needed, and returns the zone. Records are updated and created before
+// records are deleted, except for records conflicting with a CNAME record of
+// the same name, which are deleted before the records are created.
+func ApplyZonePlan(ctx context.Context, api API, plan *ZonePlan) (*Zone, error) {
+	cfg := plan.Config
+	zone := plan.Zone
+
+	var err error
+	if zone == nil {
+		opts := ZoneCreateOpts{Name: cfg.Name}
+		if cfg.Ttl > 0 {
+			ttl := cfg.Ttl
+			opts.Ttl = &ttl
+		}
+
+		zone, _, err = api.Zone.Create(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+	} else if plan.Ttl != nil {
+		zone, _, err = api.Zone.Update(ctx, zone, ZoneUpdateOpts{Name: zone.Name, Ttl: plan.Ttl})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(plan.Update) > 0 {
+		updates := make([]RecordBulkUpdateOpts, len(plan.Update))
+		for i, opts := range plan.Update {
+			opts.Zone = zone
+			updates[i] = opts
+		}
+
+		resp, _, err := api.Record.BulkUpdate(ctx, updates)
+		if err != nil {
+			return zone, err
+		}
+		if len(resp.FailedRecords) > 0 {
+			return zone, fmt.Errorf("hetzner-dns: %d records failed to update", len(resp.FailedRecords))
+		}
+	}
+
+	// A CNAME record can't coexist with other records of its name, records
+	// conflicting with the records to create are deleted beforehand.
+	conflicts := conflictingRecords(zone, plan.Create, plan.Delete)
+	for _, rec := range plan.Delete {
+		if !conflicts[rec] {
+			continue
+		}
+		if _, err := api.Record.Delete(ctx, rec); err != nil {
+			return zone, err
+		}
+	}
+
+	if len(plan.Create) > 0 {
+		creates := make([]RecordCreateOpts, len(plan.Create))
+		for i, opts := range plan.Create {
+			opts.Zone = zone
+			creates[i] = opts
+		}
+
+		resp, _, err := api.Record.BulkCreate(ctx, creates)
+		if err != nil {
+			return zone, err
+		}
+		if len(resp.InvalidRecords) > 0 {
+			return zone, fmt.Errorf("hetzner-dns: %d records are invalid", len(resp.InvalidRecords))
+		}
+	}
+
+	// The remaining records are only deleted once their replacements exist,
+	// keeping the names resolvable during the change.
+	for _, rec := range plan.Delete {
+		if conflicts[rec] {
+			continue
+		}
+		if _, err := api.Record.Delete(ctx, rec); err != nil {
+			return zone, err
+		}
+	}
+
+	if len(cfg.PrimaryServers) > 0 {
+		addrs := make([]PrimaryServerAddr, 0, len(cfg.PrimaryServers))
+		for _, ps := range cfg.PrimaryServers {
+			addrs = append(addrs, PrimaryServerAddr{Address: ps.Address, Port: ps.Port})
+		}
+
+		if _, _, err := NewSecondaryZoneClient(api.Zone, api.PrimaryServer).Reconcile(ctx, zone, addrs); err != nil {
+			return zone, err
+		}
+	}
+
+	for _, server := range plan.DeletePrimaryServers {
+		if _, err := api.PrimaryServer.Delete(ctx, server); err != nil {
+			return zone, err
+		}
+	}
+
+	return zone, nil
+}
+
+// conflictingRecords returns the records to delete which can't coexist with
+// the records to create, CNAME records to create conflict with all records of
+// their name and records to create conflict with the CNAME record of their
+// name.
+func conflictingRecords(zone *Zone, creates []RecordCreateOpts, deletes []*Record) map[*Record]bool {
+	created := map[string]bool{}
+	cnames := map[string]bool{}
+	for _, opts := range creates {
+		name := FoldName(CanonicalName(zone.Name, opts.Name))
+		created[name] = true
+		if opts.Type == RecordTypeCNAME {
+			cnames[name] = true
+		}
+	}
+
+	conflicts := map[*Record]bool{}
+	for _, rec := range deletes {
+		name := FoldName(CanonicalName(zone.Name, rec.Name))
+		if cnames[name] || (rec.Type == RecordTypeCNAME && created[name]) {
+			conflicts[rec] = true
+		}
+	}
+
+	return conflicts
+}
+
+// ExportConfig returns the config of the given zones as currently stored in the API.
+func (c *Client) ExportConfig(ctx context.Context, zones ...*Zone) (*Config, error) {
+	cfg := &Config{Version: ConfigVersion}
+	for _, zone := range zones {
+		records, _, err := c.Record.listAll(ctx, RecordListOpts{ZoneID: zone.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		servers, _, err := c.PrimaryServer.List(ctx, PrimaryServerListOpts{ZoneID: zone.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Zones = append(cfg.Zones, ZoneConfigFromAPI(zone, records, servers))
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,153 @@
+package dns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClientPlanAndApplyZone(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	api.AddRecord(zoneID, "@", RecordTypeSOA, "hydrogen.ns.hetzner.com. dns.hetzner.com. 1 86400 10800 3600000 3600", 0)
+	api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 300)
+	api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.9", 300)
+	api.AddRecord(zoneID, "@", RecordTypeMX, "10 mail.hetzner.com.", 0)
+	api.AddRecord(zoneID, "old", RecordTypeTXT, "stale", 0)
+
+	cfg, err := ParseConfig(strings.NewReader(testConfigYAML))
+	if !as.NoError(err) {
+		return
+	}
+
+	plan, err := env.Client.PlanZone(env.Context, cfg.Zones[0])
+	if !as.NoError(err) {
+		return
+	}
+
+	if plan.Empty() {
+		t.Fatal("expected changes")
+	}
+	as.EqStr(zoneID, plan.Zone.ID)
+	as.EqInt(1, len(plan.Update)) // www 192.0.2.9 -> 192.0.2.2
+	as.EqInt(5, len(plan.Create)) // shop x2, backup mx, srv, caa
+	as.EqInt(1, len(plan.Delete)) // old txt
+	if plan.Ttl != nil {
+		t.Error("unexpected zone ttl update")
+	}
+
+	var operations []AuditOperation
+	client := NewClient(WithEndpoint(env.Server.URL), WithAuditSink(AuditFunc(func(event AuditEvent) error {
+		operations = append(operations, event.Operation)
+		return nil
+	})))
+
+	_, err = client.ApplyZonePlan(env.Context, plan)
+	if !as.NoError(err) {
+		return
+	}
+	as.EqInt(9, len(api.ZoneRecords(zoneID)))
+
+	// Records are deleted after their replacements have been written.
+	if as.EqInt(3, len(operations)) {
+		as.EqStr(string(AuditRecordDelete), string(operations[2]))
+	}
+
+	plan, err = env.Client.PlanZone(env.Context, cfg.Zones[0])
+	if as.NoError(err) && !plan.Empty() {
+		t.Errorf("expected empty plan but got %+v", plan)
+	}
+
+	exported, err := env.Client.ExportConfig(env.Context, &Zone{ID: zoneID, Name: "hetzner.com", Ttl: 3600})
+	if as.NoError(err) && as.NoError(exported.Validate()) {
+		plan, err = env.Client.PlanZone(env.Context, exported.Zones[0])
+		if as.NoError(err) && !plan.Empty() {
+			t.Errorf("expected empty plan for exported config but got %+v", plan)
+		}
+	}
+}
+
+func TestClientApplyZoneCreatesZone(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+
+	cfg, err := ParseConfig(strings.NewReader(testConfigYAML))
+	if !as.NoError(err) {
+		return
+	}
+
+	plan, err := env.Client.PlanZone(env.Context, cfg.Zones[0])
+	if !as.NoError(err) {
+		return
+	}
+	if plan.Zone != nil {
+		t.Error("expected zone to be created")
+	}
+	as.EqInt(8, len(plan.Create))
+
+	zone, err := env.Client.ApplyZonePlan(env.Context, plan)
+	if as.NoError(err) {
+		as.EqStr("hetzner.com", zone.Name)
+		as.EqInt(3600, zone.Ttl)
+		as.EqInt(8, len(api.ZoneRecords(zone.ID)))
+	}
+}
+
+func TestClientApplyZoneReplacesWithCNAME(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 0)
+	api.AddRecord(zoneID, "www", RecordTypeAAAA, "2001:db8::1", 0)
+	api.AddRecord(zoneID, "shop", RecordTypeCNAME, "www", 0)
+
+	cfg, err := ParseConfig(strings.NewReader(`
+version: 1
+zones:
+  - name: hetzner.com
+    records:
+      - name: www
+        type: CNAME
+        values: [shop]
+      - name: shop
+        type: A
+        values: [192.0.2.1]
+`))
+	if !as.NoError(err) {
+		return
+	}
+
+	plan, err := env.Client.PlanZone(env.Context, cfg.Zones[0])
+	if !as.NoError(err) {
+		return
+	}
+	as.EqInt(2, len(plan.Create))
+	as.EqInt(3, len(plan.Delete))
+
+	_, err = env.Client.ApplyZonePlan(env.Context, plan)
+	if !as.NoError(err) {
+		return
+	}
+
+	records := api.ZoneRecords(zoneID)
+	if as.EqInt(2, len(records)) {
+		types := map[string]string{}
+		for _, rec := range records {
+			types[rec.Name] = rec.Type
+		}
+		as.EqStr(string(RecordTypeCNAME), types["www"])
+		as.EqStr(string(RecordTypeA), types["shop"])
+	}
+}
@@ -0,0 +1,240 @@
+package dns
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfigYAML = `
+version: 1
+zones:
+  - name: hetzner.com
+    ttl: 3600
+    records:
+      - &web
+        name: www
+        type: A
+        ttl: 300
+        values: [192.0.2.1, 192.0.2.2]
+      - <<: *web
+        name: shop
+      - name: "@"
+        type: MX
+        values:
+          - preference: 10
+            exchange: mail.hetzner.com.
+          - "20 backup.hetzner.com."
+      - name: _sip._tcp
+        type: SRV
+        values:
+          - priority: 10
+            weight: 60
+            port: 5060
+            target: sip.hetzner.com.
+      - name: "@"
+        type: CAA
+        values:
+          - tag: issue
+            value: letsencrypt.org
+`
+
+func TestParseConfig(t *testing.T) {
+	as := newAssert(t)
+
+	cfg, err := ParseConfig(strings.NewReader(testConfigYAML))
+	if !as.NoError(err) {
+		return
+	}
+
+	zone := cfg.Zones[0]
+	as.EqInt(5, len(zone.Records))
+	as.EqStr("shop", zone.Records[1].Name)
+	as.EqInt(2, len(zone.Records[1].Values))
+
+	opts, err := zone.RecordCreateOpts(&Zone{ID: "1"})
+	if as.NoError(err) && as.EqInt(8, len(opts)) {
+		as.EqInt(300, *opts[0].Ttl)
+		as.EqStr("10 mail.hetzner.com.", opts[4].Value)
+		as.EqStr("20 backup.hetzner.com.", opts[5].Value)
+		as.EqStr("10 60 5060 sip.hetzner.com.", opts[6].Value)
+		as.EqStr(`0 issue "letsencrypt.org"`, opts[7].Value)
+		if opts[4].Ttl != nil {
+			t.Error("expected zone default ttl")
+		}
+		as.EqStr("1", opts[7].Zone.ID)
+	}
+
+	json := `{"version": 1, "zones": [{"name": "hetzner.de", "records": [
+		{"name": "@", "type": "MX", "values": [{"preference": 10, "exchange": "mail"}]}
+	]}]}`
+	cfg, err = ParseConfig(strings.NewReader(json))
+	if as.NoError(err) {
+		value, _ := cfg.Zones[0].Records[0].Values[0].Render(RecordTypeMX)
+		as.EqStr("10 mail", value)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	invalid := map[string]string{
+		"missing version":  "zones: []",
+		"unknown version":  "version: 2",
+		"unknown field":    "version: 1\nzone: []",
+		"duplicate zone":   "version: 1\nzones: [{name: a.com}, {name: A.com.}]",
+		"missing name":     "version: 1\nzones: [{records: []}]",
+		"unknown type":     "version: 1\nzones: [{name: a.com, records: [{name: www, type: X, values: [a]}]}]",
+		"soa":              "version: 1\nzones: [{name: a.com, records: [{name: '@', type: SOA, values: [a]}]}]",
+		"missing values":   "version: 1\nzones: [{name: a.com, records: [{name: www, type: A}]}]",
+		"duplicate value":  "version: 1\nzones: [{name: a.com, records: [{name: www, type: A, values: [a, a]}]}]",
+		"duplicate set":    "version: 1\nzones: [{name: a.com, records: [{name: www, type: A, values: [a]}, {name: WWW, type: A, values: [b]}]}]",
+		"cname coexisting": "version: 1\nzones: [{name: a.com, records: [{name: www, type: CNAME, values: [a]}, {name: www, type: TXT, values: [b]}]}]",
+		"incomplete mx":    "version: 1\nzones: [{name: a.com, records: [{name: '@', type: MX, values: [{exchange: mail}]}]}]",
+		"structured a":     "version: 1\nzones: [{name: a.com, records: [{name: www, type: A, values: [{target: a}]}]}]",
+		"unknown value":    "version: 1\nzones: [{name: a.com, records: [{name: www, type: A, values: [{addr: a}]}]}]",
+		"primary server":   "version: 1\nzones: [{name: a.com, primary_servers: [{address: ns.a.com}]}]",
+		"include":          "version: 1\ninclude: [other.yaml]",
+	}
+
+	for name, cfg := range invalid {
+		if _, err := ParseConfig(strings.NewReader(cfg)); err == nil {
+			t.Errorf("%s: missing expected error", name)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	as := newAssert(t)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"dns.yaml":            "version: 1\ninclude: [zones/*.yaml]\nzones: [{name: a.com}]",
+		"zones/b.yaml":        "zones: [{name: b.com, records: [{name: www, type: A, values: [192.0.2.1]}]}]",
+		"zones/c.yaml":        "version: 1\nzones: [{name: c.com}]",
+		"cycle/a.yaml":        "version: 1\ninclude: [b.yaml]",
+		"cycle/b.yaml":        "include: [a.yaml]",
+		"mismatch/dns.yaml":   "version: 1\ninclude: [other.yaml]",
+		"mismatch/other.yaml": "version: 2",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o700)     // nolint: errcheck
+		os.WriteFile(path, []byte(content), 0o600) // nolint: errcheck
+	}
+
+	cfg, err := LoadConfig(filepath.Join(dir, "dns.yaml"))
+	if as.NoError(err) && as.EqInt(3, len(cfg.Zones)) {
+		as.EqStr("b.com", cfg.Zones[1].Name)
+	}
+
+	_, err = LoadConfig(filepath.Join(dir, "cycle/a.yaml"))
+	as.Error(err)
+
+	_, err = LoadConfig(filepath.Join(dir, "mismatch/dns.yaml"))
+	as.Error(err)
+}
+
+func TestConfigWrite(t *testing.T) {
+	as := newAssert(t)
+
+	cfg, err := ParseConfig(strings.NewReader(testConfigYAML))
+	if !as.NoError(err) {
+		return
+	}
+
+	for _, write := range []func(*Config, *bytes.Buffer) error{
+		func(c *Config, b *bytes.Buffer) error { return c.WriteYAML(b) },
+		func(c *Config, b *bytes.Buffer) error { return c.WriteJSON(b) },
+	} {
+		buf := &bytes.Buffer{}
+		if !as.NoError(write(cfg, buf)) {
+			continue
+		}
+
+		parsed, err := ParseConfig(buf)
+		if as.NoError(err) {
+			expected, _ := cfg.Zones[0].RecordCreateOpts(nil)
+			actual, _ := parsed.Zones[0].RecordCreateOpts(nil)
+			if as.EqInt(len(expected), len(actual)) {
+				for i := range expected {
+					as.EqStr(expected[i].Value, actual[i].Value)
+				}
+			}
+		}
+	}
+}
+
+func TestParseRecordValue(t *testing.T) {
+	as := newAssert(t)
+
+	for typ, value := range map[RecordType]string{
+		RecordTypeMX:  "10 mail.hetzner.com.",
+		RecordTypeSRV: "10 60 5060 sip.hetzner.com.",
+		RecordTypeCAA: `128 issue "letsencrypt.org; validationmethods=dns-01"`,
+		RecordTypeA:   "192.0.2.1",
+	} {
+		v := ParseRecordValue(typ, value)
+		if typ != RecordTypeA && !v.structured() {
+			t.Errorf("expected structured %s value", typ)
+		}
+
+		rendered, err := v.Render(typ)
+		if as.NoError(err) {
+			as.EqStr(value, rendered)
+		}
+	}
+
+	v := ParseRecordValue(RecordTypeMX, "mail.hetzner.com.")
+	as.EqStr("mail.hetzner.com.", v.Value)
+
+	// CAA values use zone file escapes rather than Go escapes.
+	flags := 0
+	caa := RecordValue{Flags: &flags, Tag: "iodef", Value: "mailto:\"dns\"\tcafé@hetzner.com"}
+	rendered, err := caa.Render(RecordTypeCAA)
+	if as.NoError(err) {
+		as.EqStr(`0 iodef "mailto:\"dns\"\009café@hetzner.com"`, rendered)
+		as.EqStr(caa.Value, ParseRecordValue(RecordTypeCAA, rendered).Value)
+	}
+}
+
+func TestZoneConfigFromAPIKeepsTtls(t *testing.T) {
+	as := newAssert(t)
+
+	zone := &Zone{ID: "1", Name: "hetzner.com", Ttl: 3600}
+	records := []*Record{
+		{ID: "1", Zone: zone, Name: "www", Type: RecordTypeA, Value: "192.0.2.1", Ttl: 60},
+		{ID: "2", Zone: zone, Name: "www", Type: RecordTypeA, Value: "192.0.2.2", Ttl: 300},
+		{ID: "3", Zone: zone, Name: "www", Type: RecordTypeA, Value: "192.0.2.3", Ttl: 300},
+		{ID: "4", Zone: zone, Name: "@", Type: RecordTypeMX, Value: "10 mail.hetzner.com.", Ttl: 0},
+	}
+
+	cfg := ZoneConfigFromAPI(zone, records, nil)
+	if !as.NoError(cfg.Validate()) || !as.EqInt(2, len(cfg.Records)) {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	if !as.NoError((&Config{Version: ConfigVersion, Zones: []*ZoneConfig{cfg}}).WriteYAML(buf)) {
+		return
+	}
+	parsed, err := ParseConfig(buf)
+	if !as.NoError(err) {
+		return
+	}
+
+	opts, err := parsed.Zones[0].RecordCreateOpts(zone)
+	if !as.NoError(err) || !as.EqInt(4, len(opts)) {
+		return
+	}
+	for i, ttl := range []int{60, 300, 300} {
+		if opts[i].Ttl == nil {
+			t.Errorf("expected ttl %d for %s", ttl, opts[i].Value)
+			continue
+		}
+		as.EqInt(ttl, *opts[i].Ttl)
+	}
+	if opts[3].Ttl != nil {
+		t.Errorf("unexpected mx ttl %d", *opts[3].Ttl)
+	}
+}
@@ -0,0 +1,26 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+// ErrConflict is returned by updates with IfUnmodified set when the resource
+// was modified since it was read.
+var ErrConflict = errors.New("hetzner-dns: resource was modified concurrently")
+
+// checkUnmodified returns ErrConflict when the modified time of the
+// resource as read by the caller differs from its current modified time.
+func checkUnmodified(read, current schema.HdnsTime) error {
+	if read.IsZero() {
+		return errors.New("modified time required for IfUnmodified")
+	}
+
+	if !read.Equal(current) {
+		return fmt.Errorf("%w: modified at %s, read at %s", ErrConflict, current, read)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRecordClientUpdateIfUnmodified(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	id := api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 0)
+	zone := &Zone{ID: zoneID}
+
+	rec, _, err := env.Client.Record.GetByID(env.Context, id)
+	if !as.NoError(err) {
+		return
+	}
+
+	opts := RecordUpdateOpts{Name: "www", Type: RecordTypeA, Value: "192.0.2.2", Zone: zone, IfUnmodified: true}
+	updated, _, err := env.Client.Record.Update(env.Context, rec, opts)
+	if !as.NoError(err) {
+		return
+	}
+
+	// The record read before the first update is stale now.
+	opts.Value = "192.0.2.3"
+	_, _, err = env.Client.Record.Update(env.Context, rec, opts)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("expected conflict but got %v", err)
+	}
+	as.EqStr("192.0.2.2", api.Records[id].Value)
+	as.EqInt(1, api.Calls["PUT /records/"+id])
+
+	_, _, err = env.Client.Record.Update(env.Context, updated, opts)
+	if as.NoError(err) {
+		as.EqStr("192.0.2.3", api.Records[id].Value)
+	}
+
+	_, _, err = env.Client.Record.Update(env.Context, &Record{ID: id}, opts)
+	as.Error(err)
+
+	opts.IfUnmodified = false
+	_, _, err = env.Client.Record.Update(env.Context, rec, opts)
+	as.NoError(err)
+}
+
+func TestZoneClientUpdateIfUnmodified(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+
+	zone, _, err := env.Client.Zone.GetByID(env.Context, zoneID)
+	if !as.NoError(err) {
+		return
+	}
+
+	ttl := 600
+	opts := ZoneUpdateOpts{Name: "hetzner.com", Ttl: &ttl, IfUnmodified: true}
+	_, _, err = env.Client.Zone.Update(env.Context, zone, opts)
+	if !as.NoError(err) {
+		return
+	}
+
+	_, _, err = env.Client.Zone.Update(env.Context, zone, opts)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("expected conflict but got %v", err)
+	}
+	as.EqInt(1, api.Calls["PUT /zones/"+zoneID])
+}
@@ -0,0 +1,416 @@
+package dns
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mdns "github.com/miekg/dns"
+)
+
+// TLSAUsage is the certificate usage of a TLSA record, see RFC 7218.
+type TLSAUsage uint8
+
+const (
+	TLSAUsagePKIXTA TLSAUsage = 0
+	TLSAUsagePKIXEE TLSAUsage = 1
+	TLSAUsageDANETA TLSAUsage = 2
+	TLSAUsageDANEEE TLSAUsage = 3
+)
+
+// TLSASelector is the part of the certificate a TLSA record matches.
+type TLSASelector uint8
+
+const (
+	// TLSASelectorCert matches the full certificate.
+	TLSASelectorCert TLSASelector = 0
+	// TLSASelectorSPKI matches the subject public key info, which stays the
+	// same when a certificate is renewed with the same key.
+	TLSASelectorSPKI TLSASelector = 1
+)
+
+// TLSAMatchingType is how a TLSA record matches the selected data.
+type TLSAMatchingType uint8
+
+const (
+	TLSAMatchingFull   TLSAMatchingType = 0
+	TLSAMatchingSHA256 TLSAMatchingType = 1
+	TLSAMatchingSHA512 TLSAMatchingType = 2
+)
+
+// TLSA is the value of a TLSA or DANE record, see RFC 6698.
+type TLSA struct {
+	Usage        TLSAUsage
+	Selector     TLSASelector
+	MatchingType TLSAMatchingType
+	// Data is the hex encoded certificate association data.
+	Data string
+}
+
+// NewTLSA returns the TLSA record of the certificate.
+func NewTLSA(cert *x509.Certificate, usage TLSAUsage, selector TLSASelector, matchingType TLSAMatchingType) (*TLSA, error) {
+	var der []byte
+	switch selector {
+	case TLSASelectorCert:
+		der = cert.Raw
+	case TLSASelectorSPKI:
+		der = cert.RawSubjectPublicKeyInfo
+	default:
+		return nil, fmt.Errorf("tlsa: invalid selector %d", selector)
+	}
+
+	return newTLSA(der, usage, selector, matchingType)
+}
+
+// NewTLSAFromPublicKey returns the TLSA record of the public key, which
+// selects the subject public key info of the certificates of the key.
+func NewTLSAFromPublicKey(key crypto.PublicKey, usage TLSAUsage, matchingType TLSAMatchingType) (*TLSA, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("tlsa: %w", err)
+	}
+
+	return newTLSA(der, usage, TLSASelectorSPKI, matchingType)
+}
+
+func newTLSA(der []byte, usage TLSAUsage, selector TLSASelector, matchingType TLSAMatchingType) (*TLSA, error) {
+	data, err := tlsaData(der, matchingType)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &TLSA{Usage: usage, Selector: selector, MatchingType: matchingType, Data: data}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// tlsaData returns the hex encoded association data of der.
+func tlsaData(der []byte, matchingType TLSAMatchingType) (string, error) {
+	switch matchingType {
+	case TLSAMatchingFull:
+		return hex.EncodeToString(der), nil
+	case TLSAMatchingSHA256:
+		sum := sha256.Sum256(der)
+		return hex.EncodeToString(sum[:]), nil
+	case TLSAMatchingSHA512:
+		sum := sha512.Sum512(der)
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	return "", fmt.Errorf("tlsa: invalid matching type %d", matchingType)
+}
+
+// ParseTLSA parses and validates the value of a TLSA or DANE record.
+func ParseTLSA(value string) (*TLSA, error) {
+	t, err := parseTLSA(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// parseTLSA parses the fields of the value of a TLSA or DANE record without
+// validating their meaning.
+func parseTLSA(value string) (*TLSA, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return nil, errors.New("tlsa: usage, selector, matching type and data required")
+	}
+
+	var nums [3]uint8
+	for i, f := range fields[:3] {
+		n, err := strconv.ParseUint(f, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("tlsa: invalid number %s", f)
+		}
+		nums[i] = uint8(n)
+	}
+
+	t := &TLSA{
+		Usage:        TLSAUsage(nums[0]),
+		Selector:     TLSASelector(nums[1]),
+		MatchingType: TLSAMatchingType(nums[2]),
+		Data:         strings.ToLower(strings.Join(fields[3:], "")),
+	}
+	if _, err := hex.DecodeString(t.Data); err != nil {
+		return nil, errors.New("tlsa: data must be hex encoded")
+	}
+
+	return t, nil
+}
+
+// String returns the value of the TLSA record.
+func (t *TLSA) String() string {
+	return fmt.Sprintf("%d %d %d %s", t.Usage, t.Selector, t.MatchingType, t.Data)
+}
+
+// Validate checks if usage, selector and matching type are defined and the
+// data is valid for them: a digest of the right length or, for full
+// matching, a certificate or public key.
+func (t *TLSA) Validate() error {
+	if t.Usage > TLSAUsageDANEEE {
+		return fmt.Errorf("tlsa: invalid usage %d", t.Usage)
+	}
+	if t.Selector > TLSASelectorSPKI {
+		return fmt.Errorf("tlsa: invalid selector %d", t.Selector)
+	}
+
+	data, err := hex.DecodeString(t.Data)
+	if err != nil || len(data) == 0 {
+		return errors.New("tlsa: data must be hex encoded")
+	}
+
+	switch t.MatchingType {
+	case TLSAMatchingFull:
+		if t.Selector == TLSASelectorCert {
+			_, err = x509.ParseCertificate(data)
+		} else {
+			_, err = x509.ParsePKIXPublicKey(data)
+		}
+		if err != nil {
+			return fmt.Errorf("tlsa: invalid data: %w", err)
+		}
+	case TLSAMatchingSHA256:
+		if len(data) != sha256.Size {
+			return fmt.Errorf("tlsa: sha-256 data must have %d bytes", sha256.Size)
+		}
+	case TLSAMatchingSHA512:
+		if len(data) != sha512.Size {
+			return fmt.Errorf("tlsa: sha-512 data must have %d bytes", sha512.Size)
+		}
+	default:
+		return fmt.Errorf("tlsa: invalid matching type %d", t.MatchingType)
+	}
+
+	return nil
+}
+
+// Matches reports whether the record matches the certificate. The usage is
+// not checked.
+func (t *TLSA) Matches(cert *x509.Certificate) bool {
+	der := cert.Raw
+	if t.Selector == TLSASelectorSPKI {
+		der = cert.RawSubjectPublicKeyInfo
+	}
+
+	data, err := tlsaData(der, t.MatchingType)
+	return err == nil && strings.EqualFold(data, t.Data)
+}
+
+// TLSAName returns the name of the TLSA record of a service, e.g.
+// "_443._tcp.www" for port 443 over tcp on the host www. The host is
+// relative to the zone, "@" is the zone apex.
+func TLSAName(port int, protocol, host string) string {
+	name := fmt.Sprintf("_%d._%s", port, strings.ToLower(protocol))
+	if host == "" || host == "@" {
+		return name
+	}
+
+	return name + "." + host
+}
+
+// RecordCreateOpts returns the options to create the TLSA record with the
+// given name in the zone, see TLSAName.
+func (t *TLSA) RecordCreateOpts(zone *Zone, name string) (RecordCreateOpts, error) {
+	if err := t.Validate(); err != nil {
+		return RecordCreateOpts{}, err
+	}
+
+	return RecordCreateOpts{Name: name, Type: RecordTypeTLSA, Value: t.String(), Zone: zone}, nil
+}
+
+// DSDigestType is the digest algorithm of a DS record.
+type DSDigestType uint8
+
+const (
+	DSDigestSHA1   = DSDigestType(mdns.SHA1)
+	DSDigestSHA256 = DSDigestType(mdns.SHA256)
+	DSDigestSHA384 = DSDigestType(mdns.SHA384)
+)
+
+// dsDigestSizes holds the digest sizes of the supported digest types.
+var dsDigestSizes = map[DSDigestType]int{
+	DSDigestSHA1:   20,
+	DSDigestSHA256: 32,
+	DSDigestSHA384: 48,
+}
+
+// DS is the value of a delegation signer record, see RFC 4034 section 5.
+type DS struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType DSDigestType
+	// Digest is the hex encoded digest of the DNSKEY.
+	Digest string
+}
+
+// NewDS returns the DS record of the DNSKEY of the child zone, given in zone
+// file format, e.g. "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0d...".
+func NewDS(childZone, dnskey string, digestType DSDigestType) (*DS, error) {
+	key, err := parseDNSKEY(childZone, dnskey)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dsDigestSizes[digestType]; !ok {
+		return nil, fmt.Errorf("ds: unsupported digest type %d", digestType)
+	}
+
+	ds := key.ToDS(uint8(digestType))
+	if ds == nil {
+		return nil, errors.New("ds: can not compute digest of dnskey")
+	}
+
+	return &DS{
+		KeyTag:     ds.KeyTag,
+		Algorithm:  ds.Algorithm,
+		DigestType: DSDigestType(ds.DigestType),
+		Digest:     strings.ToLower(ds.Digest),
+	}, nil
+}
+
+// parseDNSKEY parses a DNSKEY value of zone which must be a zone key.
+func parseDNSKEY(zone, value string) (*mdns.DNSKEY, error) {
+	rr, err := mdns.NewRR(fmt.Sprintf("%s 3600 IN DNSKEY %s", mdns.Fqdn(zone), value))
+	if err != nil {
+		return nil, fmt.Errorf("dnskey: %w", err)
+	}
+	key, ok := rr.(*mdns.DNSKEY)
+	if !ok {
+		return nil, errors.New("dnskey: invalid value")
+	}
+	if key.Flags&mdns.ZONE == 0 {
+		return nil, errors.New("dnskey: not a zone key")
+	}
+
+	return key, nil
+}
+
+// ParseDS parses and validates the value of a DS record.
+func ParseDS(value string) (*DS, error) {
+	ds, err := parseDS(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ds, nil
+}
+
+// parseDS parses the fields of the value of a DS record without validating
+// their meaning.
+func parseDS(value string) (*DS, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return nil, errors.New("ds: key tag, algorithm, digest type and digest required")
+	}
+
+	keyTag, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("ds: invalid key tag %s", fields[0])
+	}
+	algorithm, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("ds: invalid algorithm %s", fields[1])
+	}
+	digestType, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("ds: invalid digest type %s", fields[2])
+	}
+
+	ds := &DS{
+		KeyTag:     uint16(keyTag),
+		Algorithm:  uint8(algorithm),
+		DigestType: DSDigestType(digestType),
+		Digest:     strings.ToLower(strings.Join(fields[3:], "")),
+	}
+	if _, err := hex.DecodeString(ds.Digest); err != nil {
+		return nil, errors.New("ds: digest must be hex encoded")
+	}
+
+	return ds, nil
+}
+
+// String returns the value of the DS record.
+func (d *DS) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest)
+}
+
+// Validate checks if the algorithm and digest type are known and the digest
+// has the size of its type.
+func (d *DS) Validate() error {
+	if _, ok := mdns.AlgorithmToString[d.Algorithm]; !ok {
+		return fmt.Errorf("ds: unknown algorithm %d", d.Algorithm)
+	}
+	size, ok := dsDigestSizes[d.DigestType]
+	if !ok {
+		return fmt.Errorf("ds: unsupported digest type %d", d.DigestType)
+	}
+
+	digest, err := hex.DecodeString(d.Digest)
+	if err != nil {
+		return errors.New("ds: digest must be hex encoded")
+	}
+	if len(digest) != size {
+		return fmt.Errorf("ds: digest of type %d must have %d bytes", d.DigestType, size)
+	}
+
+	return nil
+}
+
+// Matches reports whether the record is the DS record of the DNSKEY of the
+// child zone, given in zone file format.
+func (d *DS) Matches(childZone, dnskey string) bool {
+	ds, err := NewDS(childZone, dnskey, d.DigestType)
+	if err != nil {
+		return false
+	}
+
+	want, _ := hex.DecodeString(ds.Digest)
+	got, _ := hex.DecodeString(d.Digest)
+	return ds.KeyTag == d.KeyTag && ds.Algorithm == d.Algorithm && bytes.Equal(want, got)
+}
+
+// RecordCreateOpts returns the options to create the DS record of the child
+// zone with the given name, relative to the zone, e.g. "shop" for the child
+// zone shop.example.com in the zone example.com.
+func (d *DS) RecordCreateOpts(zone *Zone, name string) (RecordCreateOpts, error) {
+	if err := d.Validate(); err != nil {
+		return RecordCreateOpts{}, err
+	}
+
+	return RecordCreateOpts{Name: name, Type: RecordTypeDS, Value: d.String(), Zone: zone}, nil
+}
+
+// validateRecordValue checks the field syntax of the values of TLSA, DANE,
+// DS and CAA records. Whether the fields are meaningful, e.g. a known digest
+// type, is left to the API; use ParseTLSA, ParseDS and ParseCAAPolicy for
+// stricter checks.
+func validateRecordValue(typ RecordType, value string) error {
+	var err error
+	switch typ {
+	case RecordTypeTLSA, RecordTypeDANE:
+		_, err = parseTLSA(value)
+	case RecordTypeDS:
+		_, err = parseDS(value)
+	case RecordTypeCAA:
+		_, err = ParseCAAProperty(value)
+	}
+
+	return err
+}
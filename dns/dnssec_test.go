@@ -0,0 +1,206 @@
+package dns
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "www.hetzner.com"},
+		DNSNames:     []string{"www.hetzner.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+func TestTLSA(t *testing.T) {
+	as := newAssert(t)
+	cert := newTestCertificate(t)
+	other := newTestCertificate(t)
+
+	tlsa, err := NewTLSA(cert, TLSAUsageDANEEE, TLSASelectorSPKI, TLSAMatchingSHA256)
+	if !as.NoError(err) {
+		return
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	as.EqStr("3 1 1 "+hex.EncodeToString(sum[:]), tlsa.String())
+	as.EqStr("_443._tcp.www", TLSAName(443, "TCP", "www"))
+	as.EqStr("_25._tcp", TLSAName(25, "tcp", "@"))
+
+	if !tlsa.Matches(cert) || tlsa.Matches(other) {
+		t.Error("expected the record to match its certificate only")
+	}
+
+	fromKey, err := NewTLSAFromPublicKey(cert.PublicKey, TLSAUsageDANEEE, TLSAMatchingSHA256)
+	if as.NoError(err) {
+		as.EqStr(tlsa.String(), fromKey.String())
+	}
+
+	for _, selector := range []TLSASelector{TLSASelectorCert, TLSASelectorSPKI} {
+		for _, matching := range []TLSAMatchingType{TLSAMatchingFull, TLSAMatchingSHA256, TLSAMatchingSHA512} {
+			tlsa, err := NewTLSA(cert, TLSAUsageDANETA, selector, matching)
+			if !as.NoError(err) {
+				continue
+			}
+			parsed, err := ParseTLSA(strings.ToUpper(tlsa.String()))
+			if as.NoError(err) {
+				as.EqStr(tlsa.String(), parsed.String())
+				if !parsed.Matches(cert) {
+					t.Errorf("expected %d %d to match", selector, matching)
+				}
+			}
+		}
+	}
+
+	opts, err := tlsa.RecordCreateOpts(&Zone{ID: "1", Name: "hetzner.com"}, TLSAName(443, "tcp", "www"))
+	if as.NoError(err) {
+		as.EqStr("_443._tcp.www", opts.Name)
+		as.EqStr(string(RecordTypeTLSA), string(opts.Type))
+		as.NoError(opts.validate())
+	}
+}
+
+func TestParseTLSAInvalid(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+
+	for name, value := range map[string]string{
+		"fields":        "3 1 1",
+		"usage":         "4 1 1 " + digest,
+		"selector":      "3 2 1 " + digest,
+		"matching type": "3 1 3 " + digest,
+		"number":        "3 1 x " + digest,
+		"hex":           "3 1 1 " + strings.Repeat("zz", 32),
+		"sha-256 size":  "3 1 1 abcd",
+		"sha-512 size":  "3 1 2 " + digest,
+		"certificate":   "3 0 0 " + digest,
+	} {
+		if _, err := ParseTLSA(value); err == nil {
+			t.Errorf("%s: expected error for %s", name, value)
+		}
+	}
+}
+
+// testDNSKEY is the DNSKEY of RFC 4034 section 5.4 with the DS record
+// testDS.
+const (
+	testDNSKEY = "256 3 5 AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="
+	testDS     = "60485 5 1 2bb183af5f22588179a53b0a98631fad1a292118"
+)
+
+func TestDS(t *testing.T) {
+	as := newAssert(t)
+
+	ds, err := NewDS("dskey.example.com", testDNSKEY, DSDigestSHA1)
+	if as.NoError(err) {
+		as.EqStr(testDS, ds.String())
+	}
+
+	parsed, err := ParseDS(strings.ToUpper(testDS))
+	if !as.NoError(err) {
+		return
+	}
+	if !parsed.Matches("dskey.example.com", testDNSKEY) {
+		t.Error("expected the record to match its key")
+	}
+	if parsed.Matches("other.example.com", testDNSKEY) {
+		t.Error("expected the record not to match the key of another zone")
+	}
+
+	sha256DS, err := NewDS("dskey.example.com", testDNSKEY, DSDigestSHA256)
+	if as.NoError(err) {
+		as.EqInt(64, len(sha256DS.Digest))
+		as.NoError(sha256DS.Validate())
+	}
+
+	_, err = NewDS("dskey.example.com", "0 3 5 AQOeiiR0GOMYkDsh", DSDigestSHA256)
+	as.Error(err)
+	_, err = NewDS("dskey.example.com", testDNSKEY, 3)
+	as.Error(err)
+
+	opts, err := parsed.RecordCreateOpts(&Zone{ID: "1", Name: "example.com"}, "dskey")
+	if as.NoError(err) {
+		as.EqStr("dskey", opts.Name)
+		as.EqStr(string(RecordTypeDS), string(opts.Type))
+		as.NoError(opts.validate())
+	}
+
+	for name, value := range map[string]string{
+		"fields":      "60485 5 1",
+		"key tag":     "70000 5 1 2bb183af5f22588179a53b0a98631fad1a292118",
+		"algorithm":   "60485 99 1 2bb183af5f22588179a53b0a98631fad1a292118",
+		"digest type": "60485 5 3 2bb183af5f22588179a53b0a98631fad1a292118",
+		"hex":         "60485 5 1 zzb183af5f22588179a53b0a98631fad1a292118",
+		"size":        "60485 5 2 2bb183af5f22588179a53b0a98631fad1a292118",
+	} {
+		if _, err := ParseDS(value); err == nil {
+			t.Errorf("%s: expected error for %s", name, value)
+		}
+	}
+}
+
+func TestRecordCreateInvalidValue(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	zone := &Zone{ID: zoneID, Name: "hetzner.com"}
+
+	// Values with invalid field syntax are refused before the request.
+	for _, opts := range []RecordCreateOpts{
+		{Name: "_443._tcp.www", Type: RecordTypeTLSA, Value: "3 1 x abcd", Zone: zone},
+		{Name: "_443._tcp.www", Type: RecordTypeDANE, Value: "3 1 1", Zone: zone},
+		{Name: "shop", Type: RecordTypeDS, Value: "60485 5 1 zz", Zone: zone},
+		{Name: "@", Type: RecordTypeCAA, Value: "0 issue", Zone: zone},
+	} {
+		_, _, err := env.Client.Record.Create(env.Context, opts)
+		as.Error(err)
+	}
+
+	_, _, err := env.Client.Record.Update(env.Context, &Record{ID: "1"}, RecordUpdateOpts{
+		Name:  "shop",
+		Type:  RecordTypeDS,
+		Value: "60485 5 1",
+		Zone:  zone,
+	})
+	as.Error(err)
+	as.EqInt(0, len(api.Records))
+
+	// Values the client doesn't know the meaning of are left to the API.
+	for _, opts := range []RecordCreateOpts{
+		{Name: "_443._tcp.www", Type: RecordTypeTLSA, Value: "3 0 0 abcd", Zone: zone},
+		{Name: "shop", Type: RecordTypeDS, Value: "60485 5 6 abcd", Zone: zone},
+	} {
+		_, _, err := env.Client.Record.Create(env.Context, opts)
+		as.NoError(err)
+	}
+	as.EqInt(2, len(api.Records))
+}
@@ -0,0 +1,227 @@
+// Package dnstest provides fakes of the dns API interfaces for tests.
+//
+// The fakes call the function field of a method when it is set and return
+// ErrNotImplemented otherwise, so a test only sets the functions of the
+// calls it expects:
+//
+//	zones := &dnstest.ZoneAPI{
+//		GetByIDFunc: func(ctx context.Context, id string) (*dns.Zone, *dns.Response, error) {
+//			return &dns.Zone{ID: id, Name: "example.com"}, nil, nil
+//		},
+//	}
+package dnstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jobstoit/hetzner-dns-go/dns"
+)
+
+// ErrNotImplemented is returned by the methods of the fakes whose function
+// field is not set.
+var ErrNotImplemented = errors.New("dnstest: not implemented")
+
+func notImplemented(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotImplemented, method)
+}
+
+// ZoneAPI is a fake of dns.ZoneAPI.
+type ZoneAPI struct {
+	ListFunc         func(ctx context.Context, opts dns.ZoneListOpts) ([]*dns.Zone, *dns.Response, error)
+	GetByIDFunc      func(ctx context.Context, id string) (*dns.Zone, *dns.Response, error)
+	CreateFunc       func(ctx context.Context, opts dns.ZoneCreateOpts) (*dns.Zone, *dns.Response, error)
+	UpdateFunc       func(ctx context.Context, zone *dns.Zone, opts dns.ZoneUpdateOpts) (*dns.Zone, *dns.Response, error)
+	DeleteFunc       func(ctx context.Context, zone *dns.Zone) (*dns.Response, error)
+	ImportFunc       func(ctx context.Context, zone *dns.Zone, file io.Reader) (*dns.Zone, *dns.Response, error)
+	ExportFunc       func(ctx context.Context, zone *dns.Zone) (io.Reader, *dns.Response, error)
+	ValidateFileFunc func(ctx context.Context, file io.Reader) (*dns.ValidatedZoneFile, *dns.Response, error)
+}
+
+var _ dns.ZoneAPI = (*ZoneAPI)(nil)
+
+// List calls ListFunc.
+func (f *ZoneAPI) List(ctx context.Context, opts dns.ZoneListOpts) ([]*dns.Zone, *dns.Response, error) {
+	if f.ListFunc == nil {
+		return nil, nil, notImplemented("ZoneAPI.List")
+	}
+	return f.ListFunc(ctx, opts)
+}
+
+// GetByID calls GetByIDFunc.
+func (f *ZoneAPI) GetByID(ctx context.Context, id string) (*dns.Zone, *dns.Response, error) {
+	if f.GetByIDFunc == nil {
+		return nil, nil, notImplemented("ZoneAPI.GetByID")
+	}
+	return f.GetByIDFunc(ctx, id)
+}
+
+// Create calls CreateFunc.
+func (f *ZoneAPI) Create(ctx context.Context, opts dns.ZoneCreateOpts) (*dns.Zone, *dns.Response, error) {
+	if f.CreateFunc == nil {
+		return nil, nil, notImplemented("ZoneAPI.Create")
+	}
+	return f.CreateFunc(ctx, opts)
+}
+
+// Update calls UpdateFunc.
+func (f *ZoneAPI) Update(ctx context.Context, zone *dns.Zone, opts dns.ZoneUpdateOpts) (*dns.Zone, *dns.Response, error) {
+	if f.UpdateFunc == nil {
+		return nil, nil, notImplemented("ZoneAPI.Update")
+	}
+	return f.UpdateFunc(ctx, zone, opts)
+}
+
+// Delete calls DeleteFunc.
+func (f *ZoneAPI) Delete(ctx context.Context, zone *dns.Zone) (*dns.Response, error) {
+	if f.DeleteFunc == nil {
+		return nil, notImplemented("ZoneAPI.Delete")
+	}
+	return f.DeleteFunc(ctx, zone)
+}
+
+// Import calls ImportFunc.
+func (f *ZoneAPI) Import(ctx context.Context, zone *dns.Zone, file io.Reader) (*dns.Zone, *dns.Response, error) {
+	if f.ImportFunc == nil {
+		return nil, nil, notImplemented("ZoneAPI.Import")
+	}
+	return f.ImportFunc(ctx, zone, file)
+}
+
+// Export calls ExportFunc.
+func (f *ZoneAPI) Export(ctx context.Context, zone *dns.Zone) (io.Reader, *dns.Response, error) {
+	if f.ExportFunc == nil {
+		return nil, nil, notImplemented("ZoneAPI.Export")
+	}
+	return f.ExportFunc(ctx, zone)
+}
+
+// ValidateFile calls ValidateFileFunc.
+func (f *ZoneAPI) ValidateFile(ctx context.Context, file io.Reader) (*dns.ValidatedZoneFile, *dns.Response, error) {
+	if f.ValidateFileFunc == nil {
+		return nil, nil, notImplemented("ZoneAPI.ValidateFile")
+	}
+	return f.ValidateFileFunc(ctx, file)
+}
+
+// RecordAPI is a fake of dns.RecordAPI.
+type RecordAPI struct {
+	ListFunc       func(ctx context.Context, opts dns.RecordListOpts) ([]*dns.Record, *dns.Response, error)
+	GetByIDFunc    func(ctx context.Context, id string) (*dns.Record, *dns.Response, error)
+	CreateFunc     func(ctx context.Context, opts dns.RecordCreateOpts) (*dns.Record, *dns.Response, error)
+	UpdateFunc     func(ctx context.Context, rec *dns.Record, opts dns.RecordUpdateOpts) (*dns.Record, *dns.Response, error)
+	DeleteFunc     func(ctx context.Context, rec *dns.Record) (*dns.Response, error)
+	BulkCreateFunc func(ctx context.Context, bulkOpts []dns.RecordCreateOpts) (*dns.RecordBulkCreateResponse, *dns.Response, error)
+	BulkUpdateFunc func(ctx context.Context, bulkOpts []dns.RecordBulkUpdateOpts) (*dns.RecordBulkUpdateResponse, *dns.Response, error)
+}
+
+var _ dns.RecordAPI = (*RecordAPI)(nil)
+
+// List calls ListFunc.
+func (f *RecordAPI) List(ctx context.Context, opts dns.RecordListOpts) ([]*dns.Record, *dns.Response, error) {
+	if f.ListFunc == nil {
+		return nil, nil, notImplemented("RecordAPI.List")
+	}
+	return f.ListFunc(ctx, opts)
+}
+
+// GetByID calls GetByIDFunc.
+func (f *RecordAPI) GetByID(ctx context.Context, id string) (*dns.Record, *dns.Response, error) {
+	if f.GetByIDFunc == nil {
+		return nil, nil, notImplemented("RecordAPI.GetByID")
+	}
+	return f.GetByIDFunc(ctx, id)
+}
+
+// Create calls CreateFunc.
+func (f *RecordAPI) Create(ctx context.Context, opts dns.RecordCreateOpts) (*dns.Record, *dns.Response, error) {
+	if f.CreateFunc == nil {
+		return nil, nil, notImplemented("RecordAPI.Create")
+	}
+	return f.CreateFunc(ctx, opts)
+}
+
+// Update calls UpdateFunc.
+func (f *RecordAPI) Update(ctx context.Context, rec *dns.Record, opts dns.RecordUpdateOpts) (*dns.Record, *dns.Response, error) {
+	if f.UpdateFunc == nil {
+		return nil, nil, notImplemented("RecordAPI.Update")
+	}
+	return f.UpdateFunc(ctx, rec, opts)
+}
+
+// Delete calls DeleteFunc.
+func (f *RecordAPI) Delete(ctx context.Context, rec *dns.Record) (*dns.Response, error) {
+	if f.DeleteFunc == nil {
+		return nil, notImplemented("RecordAPI.Delete")
+	}
+	return f.DeleteFunc(ctx, rec)
+}
+
+// BulkCreate calls BulkCreateFunc.
+func (f *RecordAPI) BulkCreate(ctx context.Context, bulkOpts []dns.RecordCreateOpts) (*dns.RecordBulkCreateResponse, *dns.Response, error) {
+	if f.BulkCreateFunc == nil {
+		return nil, nil, notImplemented("RecordAPI.BulkCreate")
+	}
+	return f.BulkCreateFunc(ctx, bulkOpts)
+}
+
+// BulkUpdate calls BulkUpdateFunc.
+func (f *RecordAPI) BulkUpdate(ctx context.Context, bulkOpts []dns.RecordBulkUpdateOpts) (*dns.RecordBulkUpdateResponse, *dns.Response, error) {
+	if f.BulkUpdateFunc == nil {
+		return nil, nil, notImplemented("RecordAPI.BulkUpdate")
+	}
+	return f.BulkUpdateFunc(ctx, bulkOpts)
+}
+
+// PrimaryServerAPI is a fake of dns.PrimaryServerAPI.
+type PrimaryServerAPI struct {
+	ListFunc    func(ctx context.Context, opts dns.PrimaryServerListOpts) ([]*dns.PrimaryServer, *dns.Response, error)
+	GetByIDFunc func(ctx context.Context, id string) (*dns.PrimaryServer, *dns.Response, error)
+	CreateFunc  func(ctx context.Context, opts dns.PrimaryServerCreateOpts) (*dns.PrimaryServer, *dns.Response, error)
+	UpdateFunc  func(ctx context.Context, server *dns.PrimaryServer, opts dns.PrimaryServerUpdateOpts) (*dns.PrimaryServer, *dns.Response, error)
+	DeleteFunc  func(ctx context.Context, server *dns.PrimaryServer) (*dns.Response, error)
+}
+
+var _ dns.PrimaryServerAPI = (*PrimaryServerAPI)(nil)
+
+// List calls ListFunc.
+func (f *PrimaryServerAPI) List(ctx context.Context, opts dns.PrimaryServerListOpts) ([]*dns.PrimaryServer, *dns.Response, error) {
+	if f.ListFunc == nil {
+		return nil, nil, notImplemented("PrimaryServerAPI.List")
+	}
+	return f.ListFunc(ctx, opts)
+}
+
+// GetByID calls GetByIDFunc.
+func (f *PrimaryServerAPI) GetByID(ctx context.Context, id string) (*dns.PrimaryServer, *dns.Response, error) {
+	if f.GetByIDFunc == nil {
+		return nil, nil, notImplemented("PrimaryServerAPI.GetByID")
+	}
+	return f.GetByIDFunc(ctx, id)
+}
+
+// Create calls CreateFunc.
+func (f *PrimaryServerAPI) Create(ctx context.Context, opts dns.PrimaryServerCreateOpts) (*dns.PrimaryServer, *dns.Response, error) {
+	if f.CreateFunc == nil {
+		return nil, nil, notImplemented("PrimaryServerAPI.Create")
+	}
+	return f.CreateFunc(ctx, opts)
+}
+
+// Update calls UpdateFunc.
+func (f *PrimaryServerAPI) Update(ctx context.Context, server *dns.PrimaryServer, opts dns.PrimaryServerUpdateOpts) (*dns.PrimaryServer, *dns.Response, error) {
+	if f.UpdateFunc == nil {
+		return nil, nil, notImplemented("PrimaryServerAPI.Update")
+	}
+	return f.UpdateFunc(ctx, server, opts)
+}
+
+// Delete calls DeleteFunc.
+func (f *PrimaryServerAPI) Delete(ctx context.Context, server *dns.PrimaryServer) (*dns.Response, error) {
+	if f.DeleteFunc == nil {
+		return nil, notImplemented("PrimaryServerAPI.Delete")
+	}
+	return f.DeleteFunc(ctx, server)
+}
@@ -0,0 +1,116 @@
+package dnstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jobstoit/hetzner-dns-go/dns"
+)
+
+// zoneNames is a function of a service under test depending on the zones API.
+func zoneNames(ctx context.Context, zones dns.ZoneAPI) ([]string, error) {
+	list, _, err := zones.List(ctx, dns.ZoneListOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(list))
+	for i, zone := range list {
+		names[i] = zone.Name
+	}
+	return names, nil
+}
+
+func TestZoneAPI(t *testing.T) {
+	zones := &ZoneAPI{
+		ListFunc: func(ctx context.Context, opts dns.ZoneListOpts) ([]*dns.Zone, *dns.Response, error) {
+			return []*dns.Zone{{Name: "hetzner.com"}, {Name: "hetzner.de"}}, nil, nil
+		},
+	}
+
+	names, err := zoneNames(context.Background(), zones)
+	if err != nil || len(names) != 2 || names[1] != "hetzner.de" {
+		t.Errorf("unexpected names %v: %v", names, err)
+	}
+
+	if _, _, err := zones.GetByID(context.Background(), "1"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("expected not implemented error but got %v", err)
+	}
+}
+
+func TestRecordAPI(t *testing.T) {
+	var created []dns.RecordCreateOpts
+	records := &RecordAPI{
+		BulkCreateFunc: func(ctx context.Context, bulkOpts []dns.RecordCreateOpts) (*dns.RecordBulkCreateResponse, *dns.Response, error) {
+			created = append(created, bulkOpts...)
+			return &dns.RecordBulkCreateResponse{}, nil, nil
+		},
+	}
+
+	var api dns.RecordAPI = records
+	if _, _, err := api.BulkCreate(context.Background(), []dns.RecordCreateOpts{{Name: "www"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0].Name != "www" {
+		t.Errorf("unexpected created records %v", created)
+	}
+
+	if _, err := api.Delete(context.Background(), &dns.Record{}); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("expected not implemented error but got %v", err)
+	}
+}
+
+func TestPrimaryServerAPI(t *testing.T) {
+	var servers dns.PrimaryServerAPI = &PrimaryServerAPI{}
+	if _, _, err := servers.List(context.Background(), dns.PrimaryServerListOpts{}); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("expected not implemented error but got %v", err)
+	}
+}
+
+func TestReplaceRRSet(t *testing.T) {
+	zone := &dns.Zone{ID: "1", Name: "hetzner.com"}
+
+	var deleted []string
+	var created []dns.RecordCreateOpts
+	records := &RecordAPI{
+		ListFunc: func(ctx context.Context, opts dns.RecordListOpts) ([]*dns.Record, *dns.Response, error) {
+			return []*dns.Record{
+				{ID: "a", Name: "www", Type: dns.RecordTypeA, Value: "192.0.2.1", Zone: zone},
+				{ID: "b", Name: "www", Type: dns.RecordTypeA, Value: "192.0.2.2", Zone: zone},
+			}, nil, nil
+		},
+		BulkCreateFunc: func(ctx context.Context, bulkOpts []dns.RecordCreateOpts) (*dns.RecordBulkCreateResponse, *dns.Response, error) {
+			created = append(created, bulkOpts...)
+			return &dns.RecordBulkCreateResponse{}, nil, nil
+		},
+		BulkUpdateFunc: func(ctx context.Context, bulkOpts []dns.RecordBulkUpdateOpts) (*dns.RecordBulkUpdateResponse, *dns.Response, error) {
+			return &dns.RecordBulkUpdateResponse{}, nil, nil
+		},
+		DeleteFunc: func(ctx context.Context, rec *dns.Record) (*dns.Response, error) {
+			deleted = append(deleted, rec.ID)
+			return nil, nil
+		},
+	}
+
+	set := &dns.RRSet{Zone: zone, Name: "www", Type: dns.RecordTypeA, Values: []string{"192.0.2.1"}}
+	if _, _, err := dns.ReplaceRRSet(context.Background(), records, set); err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 0 || len(deleted) != 1 || deleted[0] != "b" {
+		t.Errorf("unexpected changes, created %v, deleted %v", created, deleted)
+	}
+}
+
+func TestSecondaryZoneClient(t *testing.T) {
+	zones := &ZoneAPI{
+		ListFunc: func(ctx context.Context, opts dns.ZoneListOpts) ([]*dns.Zone, *dns.Response, error) {
+			return []*dns.Zone{{ID: "1", IsSecondaryDNS: true}, {ID: "2"}}, nil, nil
+		},
+	}
+
+	secondaries, _, err := dns.NewSecondaryZoneClient(zones, &PrimaryServerAPI{}).List(context.Background(), dns.ZoneListOpts{})
+	if err != nil || len(secondaries) != 1 || secondaries[0].ID != "1" {
+		t.Errorf("unexpected secondary zones %v: %v", secondaries, err)
+	}
+}
@@ -0,0 +1,281 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mdns "github.com/miekg/dns"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+// DryRunIDPrefix is the prefix of the ids of the zones, records and primary
+// servers created in dry run mode, see WithDryRun.
+const DryRunIDPrefix = "dry-run-"
+
+// WithDryRun configures the client to not send mutating requests. Creates,
+// updates, deletes and imports are validated locally and the request which
+// would be sent is written to the dry run writer, see WithDryRunWriter. Zone
+// files are only checked for their syntax, as the API validates them on
+// import. The results are
+// synthesized from the request, with created resources getting ids starting
+// with DryRunIDPrefix. Reading requests, including zone file validation, are
+// still sent to the API, except for the reads of helpers like
+// SecondaryZoneClient.Create and Client.ApplyZonePlan on resources created in
+// dry run mode, whose results are built from the requests instead.
+func WithDryRun() ClientOption {
+	return func(client *Client) {
+		client.dryRun = true
+	}
+}
+
+// WithDryRunWriter configures the writer the requests not sent in dry run
+// mode are written to, one line per request with its method, URL and body.
+func WithDryRunWriter(w io.Writer) ClientOption {
+	return func(client *Client) {
+		client.dryRunWriter = w
+	}
+}
+
+// dryRunIDs is the sequence of the ids synthesized in dry run mode.
+var dryRunIDs uint64
+
+// isMutating reports whether r changes resources of the API.
+func isMutating(r *http.Request, path string) bool {
+	switch r.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return false
+	}
+
+	return path != pathZones+"/file/validate"
+}
+
+// apiPath returns the path of r relative to the endpoint of the client.
+func (c *Client) apiPath(r *http.Request) string {
+	base := ""
+	if u, err := url.Parse(c.endpoint); err == nil {
+		base = u.Path
+	}
+
+	return strings.TrimPrefix(r.URL.Path, base)
+}
+
+// doDryRun writes the request to the dry run writer instead of sending it
+// and answers it with a synthesized response.
+func (c *Client) doDryRun(r *http.Request, path string, reqBody []byte, v interface{}) (*Response, error) {
+	if reqBody == nil && r.Body != nil {
+		// Bodies of unknown length, like zone file imports, were not read by Do.
+		var err error
+		reqBody, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.dryRunWriter != nil {
+		msg := fmt.Sprintf("hetzner-dns: dry run: %s %s", r.Method, r.URL)
+		if len(reqBody) > 0 {
+			msg += " " + string(reqBody)
+		}
+		fmt.Fprintln(c.dryRunWriter, msg)
+	}
+
+	body, err := dryRunBody(r.Method, path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("hetzner-dns: dry run: %w", err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	response := &Response{
+		Response: &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       r,
+		},
+		DryRun:  true,
+		RawBody: body,
+	}
+
+	if v != nil {
+		if w, ok := v.(io.Writer); ok {
+			_, err = io.Copy(w, bytes.NewReader(body))
+		} else {
+			err = json.Unmarshal(body, v)
+		}
+	}
+
+	return response, err
+}
+
+// dryRunBody returns the response body the API would send for a mutating
+// request with the given method, path and body.
+func dryRunBody(method, path string, reqBody []byte) ([]byte, error) {
+	now := schema.HdnsTime(time.Now().UTC().Truncate(time.Second))
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	id := ""
+	if len(segments) > 1 {
+		id = segments[1]
+	}
+
+	if method == "DELETE" {
+		return []byte("{}"), nil
+	}
+
+	switch {
+	case segments[0] == pathZones[1:] && len(segments) == 1:
+		var req schema.ZoneCreateRequest
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil, err
+		}
+		return json.Marshal(schema.ZoneResponse{Zone: schema.Zone{
+			ID:       newDryRunID(),
+			Created:  now,
+			Modified: now,
+			Name:     req.Name,
+			Status:   string(ZoneStatusPending),
+			Ttl:      intValue(req.Ttl),
+		}})
+
+	case segments[0] == pathZones[1:] && len(segments) == 2:
+		var req schema.ZoneUpdateRequest
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil, err
+		}
+		return json.Marshal(schema.ZoneResponse{Zone: schema.Zone{
+			ID:       id,
+			Modified: now,
+			Name:     req.Name,
+			Ttl:      intValue(req.Ttl),
+		}})
+
+	case segments[0] == pathZones[1:] && len(segments) == 3 && segments[2] == "import":
+		return json.Marshal(schema.ZoneResponse{Zone: schema.Zone{
+			ID:       id,
+			Modified: now,
+		}})
+
+	case segments[0] == pathRecords[1:] && len(segments) == 1:
+		var req schema.RecordCreateRequest
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil, err
+		}
+		return json.Marshal(schema.RecordResponse{Record: dryRunRecord(newDryRunID(), now, req)})
+
+	case segments[0] == pathRecords[1:] && len(segments) == 2 && id == "bulk" && method == "POST":
+		var req schema.RecordBulkCreateRequest
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil, err
+		}
+		var resp schema.RecordBulkCreateResponse
+		for _, r := range req.Records {
+			resp.Records = append(resp.Records, dryRunRecord(newDryRunID(), now, r))
+			resp.ValidRecords = append(resp.ValidRecords, schema.RecordBulkEntry(r))
+		}
+		return json.Marshal(resp)
+
+	case segments[0] == pathRecords[1:] && len(segments) == 2 && id == "bulk":
+		var req schema.RecordBulkUpdateRequest
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil, err
+		}
+		var resp schema.RecordBulkUpdateResponse
+		for _, r := range req.Records {
+			resp.Records = append(resp.Records, dryRunRecord(r.ID, now, schema.RecordCreateRequest{
+				Name:   r.Name,
+				Ttl:    r.Ttl,
+				Type:   r.Type,
+				Value:  r.Value,
+				ZoneID: r.ZoneID,
+			}))
+		}
+		return json.Marshal(resp)
+
+	case segments[0] == pathRecords[1:] && len(segments) == 2:
+		var req schema.RecordCreateRequest
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil, err
+		}
+		return json.Marshal(schema.RecordResponse{Record: dryRunRecord(id, now, req)})
+
+	case segments[0] == pathPrimaryServers[1:] && len(segments) <= 2:
+		var req schema.PrimaryServerCreateRequest
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return nil, err
+		}
+		server := schema.PrimaryServer{
+			ID:       id,
+			Modified: now,
+			ZoneID:   req.ZoneID,
+			Address:  req.Address,
+			Port:     req.Port,
+		}
+		if id == "" {
+			server.ID = newDryRunID()
+			server.Created = now
+		}
+		return json.Marshal(schema.PrimaryServerResponse{PrimaryServer: server})
+	}
+
+	return []byte("{}"), nil
+}
+
+// checkZoneFile checks the syntax of the zone file of the zone with the
+// given origin.
+func checkZoneFile(origin string, file []byte) error {
+	zp := mdns.NewZoneParser(bytes.NewReader(file), mdns.Fqdn(origin), "")
+	for _, ok := zp.Next(); ok; _, ok = zp.Next() {
+	}
+
+	return zp.Err()
+}
+
+// dryRunRecord returns the record resulting from a create or update request.
+func dryRunRecord(id string, now schema.HdnsTime, req schema.RecordCreateRequest) schema.Record {
+	rec := schema.Record{
+		Type:     req.Type,
+		ID:       id,
+		Modified: now,
+		ZoneID:   req.ZoneID,
+		Name:     req.Name,
+		Value:    req.Value,
+		Ttl:      intValue(req.Ttl),
+	}
+	if isDryRunID(id) {
+		rec.Created = now
+	}
+
+	return rec
+}
+
+// isDryRunID reports whether the id was synthesized in dry run mode, in
+// which case the resource doesn't exist in the API and can't be read.
+func isDryRunID(id string) bool {
+	return strings.HasPrefix(id, DryRunIDPrefix)
+}
+
+func newDryRunID() string {
+	return fmt.Sprintf("%s%d", DryRunIDPrefix, atomic.AddUint64(&dryRunIDs, 1))
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
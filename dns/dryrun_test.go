@@ -0,0 +1,146 @@
+package dns
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	recID := api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 0)
+
+	var log bytes.Buffer
+	var debug bytes.Buffer
+	env.Client = NewClient(WithEndpoint(env.Server.URL), WithDebugWriter(&debug), WithDryRun(), WithDryRunWriter(&log))
+	ctx := env.Context
+
+	// Reads are sent to the API.
+	zone, _, err := env.Client.Zone.GetByID(ctx, zoneID)
+	if !as.NoError(err) {
+		return
+	}
+	as.EqStr("hetzner.com", zone.Name)
+
+	ttl := 600
+	created, resp, err := env.Client.Zone.Create(ctx, ZoneCreateOpts{Name: "hetzner.de", Ttl: &ttl})
+	if as.NoError(err) {
+		as.EqStr("hetzner.de", created.Name)
+		as.EqInt(600, created.Ttl)
+		if !strings.HasPrefix(created.ID, DryRunIDPrefix) {
+			t.Errorf("unexpected id %q", created.ID)
+		}
+		if !resp.DryRun {
+			t.Error("expected dry run response")
+		}
+	}
+
+	_, _, err = env.Client.Zone.Update(ctx, zone, ZoneUpdateOpts{Name: "hetzner.com", Ttl: &ttl})
+	as.NoError(err)
+
+	_, _, err = env.Client.Zone.Import(ctx, zone, strings.NewReader("www IN A 192.0.2.1\n"))
+	as.NoError(err)
+
+	rec, _, err := env.Client.Record.Create(ctx, RecordCreateOpts{Name: "api", Type: RecordTypeA, Value: "192.0.2.2", Zone: zone})
+	if as.NoError(err) {
+		as.EqStr("api", rec.Name)
+		as.EqStr(zoneID, rec.Zone.ID)
+	}
+
+	updated, _, err := env.Client.Record.Update(ctx, &Record{ID: recID}, RecordUpdateOpts{Name: "www", Type: RecordTypeA, Value: "192.0.2.3", Zone: zone})
+	if as.NoError(err) {
+		as.EqStr(recID, updated.ID)
+		as.EqStr("192.0.2.3", updated.Value)
+	}
+
+	bulk, _, err := env.Client.Record.BulkCreate(ctx, []RecordCreateOpts{
+		{Name: "a", Type: RecordTypeA, Value: "192.0.2.4", Zone: zone},
+		{Name: "b", Type: RecordTypeA, Value: "192.0.2.5", Zone: zone},
+	})
+	if as.NoError(err) {
+		as.EqInt(2, len(bulk.Records))
+		as.EqInt(2, len(bulk.ValidRecords))
+	}
+
+	bulkUpdated, _, err := env.Client.Record.BulkUpdate(ctx, []RecordBulkUpdateOpts{
+		{ID: recID, Name: "www", Type: RecordTypeA, Value: "192.0.2.6", Zone: zone},
+	})
+	if as.NoError(err) && as.EqInt(1, len(bulkUpdated.Records)) {
+		as.EqStr(recID, bulkUpdated.Records[0].ID)
+	}
+
+	_, err = env.Client.Record.Delete(ctx, &Record{ID: recID})
+	as.NoError(err)
+	_, err = env.Client.Zone.Delete(ctx, zone)
+	as.NoError(err)
+
+	// Invalid requests fail without being logged.
+	_, _, err = env.Client.Record.Create(ctx, RecordCreateOpts{Name: "api", Type: RecordTypeA, Zone: zone})
+	as.Error(err)
+	_, _, err = env.Client.Zone.Import(ctx, zone, strings.NewReader("www IN A 192.0.2\n"))
+	as.Error(err)
+
+	as.EqInt(1, len(api.Zones))
+	as.EqInt(1, len(api.Records))
+	as.EqStr("192.0.2.1", api.Records[recID].Value)
+	for call := range api.Calls {
+		if !strings.HasPrefix(call, "GET ") {
+			t.Errorf("unexpected call %s", call)
+		}
+	}
+
+	as.EqInt(9, strings.Count(log.String(), "hetzner-dns: dry run: "))
+	if strings.Contains(debug.String(), "hetzner-dns: dry run: ") {
+		t.Error("unexpected dry run log in debug writer")
+	}
+	if !strings.Contains(log.String(), "POST "+env.Server.URL+"/zones/"+zoneID+"/import www IN A 192.0.2.1") {
+		t.Errorf("import not logged:\n%s", log.String())
+	}
+}
+
+func TestDryRunApplyZonePlan(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	env.Client = NewClient(WithEndpoint(env.Server.URL), WithDryRun())
+
+	cfg, err := ParseConfig(strings.NewReader(testConfigYAML))
+	if !as.NoError(err) {
+		return
+	}
+	cfg.Zones[0].PrimaryServers = []PrimaryServerConfig{{Address: "192.0.2.53"}}
+
+	plan, err := env.Client.PlanZone(env.Context, cfg.Zones[0])
+	if !as.NoError(err) {
+		return
+	}
+
+	zone, err := env.Client.ApplyZonePlan(env.Context, plan)
+	if as.NoError(err) {
+		as.EqStr("hetzner.com", zone.Name)
+		if !isDryRunID(zone.ID) {
+			t.Errorf("unexpected id %q", zone.ID)
+		}
+	}
+	as.EqInt(0, len(api.Zones))
+	as.EqInt(0, len(api.Records))
+	as.EqInt(0, len(api.PrimaryServers))
+
+	secondary, _, err := env.Client.SecondaryZone.Create(env.Context, SecondaryZoneCreateOpts{
+		Name:           "hetzner.de",
+		PrimaryServers: []PrimaryServerAddr{{Address: "192.0.2.53"}},
+	})
+	if as.NoError(err) && as.EqInt(1, len(secondary.PrimaryServers)) {
+		as.EqStr(secondary.Zone.ID, secondary.PrimaryServers[0].Zone.ID)
+	}
+	as.EqInt(0, len(api.Zones))
+}
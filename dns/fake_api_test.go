@@ -0,0 +1,269 @@
+package dns
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+// fakeAPI is an in memory implementation of the zones, records and primary
+// servers API.
+type fakeAPI struct {
+	mu             sync.Mutex
+	nextID         int
+	Zones          map[string]*schema.Zone
+	Records        map[string]*schema.Record
+	PrimaryServers map[string]*schema.PrimaryServer
+	// Calls counts the requests by method and path, e.g. "GET /zones".
+	Calls map[string]int
+}
+
+func newFakeAPI(env testEnv) *fakeAPI {
+	api := &fakeAPI{
+		Zones:          map[string]*schema.Zone{},
+		Records:        map[string]*schema.Record{},
+		PrimaryServers: map[string]*schema.PrimaryServer{},
+		Calls:          map[string]int{},
+	}
+
+	env.Mux.HandleFunc(pathZones, api.handle(api.zones))
+	env.Mux.HandleFunc(pathZones+"/", api.handle(api.zone))
+	env.Mux.HandleFunc(pathRecords, api.handle(api.records))
+	env.Mux.HandleFunc(pathRecords+"/", api.handle(api.record))
+	handlePrimaryServers(env, api.PrimaryServers)
+
+	return api
+}
+
+func (api *fakeAPI) id() string {
+	api.nextID++
+	return fmt.Sprintf("id%04d", api.nextID)
+}
+
+// now returns a distinct modification time for every write.
+func (api *fakeAPI) now() schema.HdnsTime {
+	api.nextID++
+	return schema.HdnsTime(time.Date(2020, 1, 1, 0, 0, api.nextID, 0, time.UTC))
+}
+
+// AddZone adds a zone with the given name and returns its id.
+func (api *fakeAPI) AddZone(name string, ttl int) string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	zone := &schema.Zone{ID: api.id(), Name: name, Ttl: ttl, Status: string(ZoneStatusVerified), Modified: api.now()}
+	api.Zones[zone.ID] = zone
+	return zone.ID
+}
+
+// AddRecord adds a record to the zone and returns its id.
+func (api *fakeAPI) AddRecord(zoneID, name string, typ RecordType, value string, ttl int) string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	rec := &schema.Record{ID: api.id(), ZoneID: zoneID, Name: name, Type: string(typ), Value: value, Ttl: ttl, Modified: api.now()}
+	api.Records[rec.ID] = rec
+	return rec.ID
+}
+
+// ZoneRecords returns the records of the zone ordered by id.
+func (api *fakeAPI) ZoneRecords(zoneID string) []schema.Record {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return api.zoneRecords(zoneID)
+}
+
+func (api *fakeAPI) zoneRecords(zoneID string) []schema.Record {
+	records := []schema.Record{}
+	for _, rec := range api.Records {
+		if zoneID == "" || rec.ZoneID == zoneID {
+			records = append(records, *rec)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	return records
+}
+
+func (api *fakeAPI) handle(fn func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		api.Calls[r.Method+" "+r.URL.Path]++
+		fn(w, r)
+	}
+}
+
+func (api *fakeAPI) zones(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		resp := schema.ZoneListResponse{Zones: []schema.Zone{}}
+		for _, zone := range api.Zones {
+			if name := r.URL.Query().Get("name"); name == "" || name == zone.Name {
+				resp.Zones = append(resp.Zones, *zone)
+			}
+		}
+		sort.Slice(resp.Zones, func(i, j int) bool { return resp.Zones[i].ID < resp.Zones[j].ID })
+		json.NewEncoder(w).Encode(resp) // nolint: errcheck
+	case http.MethodPost:
+		var body schema.ZoneCreateRequest
+		json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+		zone := &schema.Zone{ID: api.id(), Name: body.Name, Status: string(ZoneStatusPending), Modified: api.now()}
+		if body.Ttl != nil {
+			zone.Ttl = *body.Ttl
+		}
+		api.Zones[zone.ID] = zone
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(schema.ZoneResponse{Zone: *zone}) // nolint: errcheck
+	}
+}
+
+func (api *fakeAPI) zone(w http.ResponseWriter, r *http.Request) {
+	zone, ok := api.Zones[strings.TrimPrefix(r.URL.Path, pathZones+"/")]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(schema.ZoneResponse{Zone: *zone}) // nolint: errcheck
+	case http.MethodPut:
+		var body schema.ZoneUpdateRequest
+		json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+		zone.Name = body.Name
+		zone.Modified = api.now()
+		if body.Ttl != nil {
+			zone.Ttl = *body.Ttl
+		}
+		json.NewEncoder(w).Encode(schema.ZoneResponse{Zone: *zone}) // nolint: errcheck
+	case http.MethodDelete:
+		delete(api.Zones, zone.ID)
+	}
+}
+
+func (api *fakeAPI) createRecord(body schema.RecordCreateRequest) *schema.Record {
+	rec := &schema.Record{ID: api.id(), ZoneID: body.ZoneID, Name: body.Name, Type: body.Type, Value: body.Value, Modified: api.now()}
+	if body.Ttl != nil {
+		rec.Ttl = *body.Ttl
+	}
+	api.Records[rec.ID] = rec
+
+	return rec
+}
+
+// cnameConflict reports whether the record to create can't coexist with the
+// records of its name, as the API refuses CNAME records next to other records.
+func (api *fakeAPI) cnameConflict(body schema.RecordCreateRequest) bool {
+	for _, rec := range api.Records {
+		if rec.ZoneID != body.ZoneID || !strings.EqualFold(rec.Name, body.Name) {
+			continue
+		}
+		if rec.Type == string(RecordTypeCNAME) || body.Type == string(RecordTypeCNAME) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (api *fakeAPI) records(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		resp := schema.RecordListResponse{Records: api.zoneRecords(r.URL.Query().Get("zone_id"))}
+		json.NewEncoder(w).Encode(resp) // nolint: errcheck
+	case http.MethodPost:
+		var body schema.RecordCreateRequest
+		json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+		if api.cnameConflict(body) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+
+		rec := api.createRecord(body)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(schema.RecordResponse{Record: *rec}) // nolint: errcheck
+	}
+}
+
+func (api *fakeAPI) record(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, pathRecords+"/")
+
+	if id == "bulk" {
+		switch r.Method {
+		case http.MethodPost:
+			var body schema.RecordBulkCreateRequest
+			json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+			var resp schema.RecordBulkCreateResponse
+			for _, req := range body.Records {
+				if api.cnameConflict(req) {
+					resp.InvalidRecords = append(resp.InvalidRecords, schema.RecordBulkEntry{
+						Name: req.Name, Ttl: req.Ttl, Type: req.Type, Value: req.Value, ZoneID: req.ZoneID,
+					})
+					continue
+				}
+				resp.Records = append(resp.Records, *api.createRecord(req))
+			}
+			json.NewEncoder(w).Encode(resp) // nolint: errcheck
+		case http.MethodPut:
+			var body schema.RecordBulkUpdateRequest
+			json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+			var resp schema.RecordBulkUpdateResponse
+			for _, req := range body.Records {
+				rec, ok := api.Records[req.ID]
+				if !ok {
+					resp.FailedRecords = append(resp.FailedRecords, schema.RecordBulkEntry{
+						Name: req.Name, Ttl: req.Ttl, Type: req.Type, Value: req.Value, ZoneID: req.ZoneID,
+					})
+					continue
+				}
+
+				rec.Name, rec.Type, rec.Value, rec.Ttl = req.Name, req.Type, req.Value, 0
+				rec.Modified = api.now()
+				if req.Ttl != nil {
+					rec.Ttl = *req.Ttl
+				}
+				resp.Records = append(resp.Records, *rec)
+			}
+			json.NewEncoder(w).Encode(resp) // nolint: errcheck
+		}
+		return
+	}
+
+	rec, ok := api.Records[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(schema.RecordResponse{Record: *rec}) // nolint: errcheck
+	case http.MethodPut:
+		var body schema.RecordUpdateRequest
+		json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+		rec.Name, rec.Type, rec.Value, rec.Ttl = body.Name, body.Type, body.Value, 0
+		rec.Modified = api.now()
+		if body.Ttl != nil {
+			rec.Ttl = *body.Ttl
+		}
+		json.NewEncoder(w).Encode(schema.RecordResponse{Record: *rec}) // nolint: errcheck
+	case http.MethodDelete:
+		delete(api.Records, id)
+	}
+}
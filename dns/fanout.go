@@ -0,0 +1,131 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ZoneError is the error of an operation on a single zone.
+type ZoneError struct {
+	Zone *Zone
+	Err  error
+}
+
+func (e *ZoneError) Error() string {
+	return fmt.Sprintf("zone %s: %s", e.Zone.Name, e.Err)
+}
+
+func (e *ZoneError) Unwrap() error {
+	return e.Err
+}
+
+// ZoneErrors holds the errors of an operation on multiple zones, in the
+// order of the zones.
+type ZoneErrors []*ZoneError
+
+func (e ZoneErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("hetzner-dns: %d zones failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// listAll returns all zones with the given parameters, requesting all pages.
+func (c ZoneClient) listAll(ctx context.Context, opts ZoneListOpts) ([]*Zone, *Response, error) {
+	return listAllZones(ctx, c, opts)
+}
+
+// ForEachZone calls fn for every zone of the account with up to concurrency
+// calls at a time. The calls share the rate limit of the client, see
+// WithRateLimit. Once ctx is done no further calls are started. Failed calls
+// and the zones not processed due to cancellation are returned as
+// ZoneErrors.
+func (c *Client) ForEachZone(ctx context.Context, concurrency int, fn func(ctx context.Context, zone *Zone) error) error {
+	zones, _, err := c.Zone.listAll(ctx, ZoneListOpts{})
+	if err != nil {
+		return err
+	}
+
+	return forZones(ctx, zones, concurrency, fn)
+}
+
+// forZones calls fn for the zones with up to concurrency calls at a time.
+func forZones(ctx context.Context, zones []*Zone, concurrency int, fn func(ctx context.Context, zone *Zone) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs := make([]error, len(zones))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(zones); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(ctx, zones[i])
+			}
+		}()
+	}
+
+	for i := range zones {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	var zoneErrs ZoneErrors
+	for i, err := range errs {
+		if err != nil {
+			zoneErrs = append(zoneErrs, &ZoneError{Zone: zones[i], Err: err})
+		}
+	}
+	if len(zoneErrs) > 0 {
+		return zoneErrs
+	}
+
+	return nil
+}
+
+// ListForZones returns the records of the zones by zone id, see
+// ListForZones.
+func (c RecordClient) ListForZones(ctx context.Context, zones []*Zone, concurrency int) (map[string][]*Record, error) {
+	return ListForZones(ctx, c, zones, concurrency)
+}
+
+// ListForZones returns the records of the zones by zone id, listing up to
+// concurrency zones at a time through api. The records of the zones listed
+// successfully are returned together with ZoneErrors for the zones which
+// failed.
+func ListForZones(ctx context.Context, api RecordAPI, zones []*Zone, concurrency int) (map[string][]*Record, error) {
+	var mu sync.Mutex
+	result := make(map[string][]*Record, len(zones))
+
+	err := forZones(ctx, zones, concurrency, func(ctx context.Context, zone *Zone) error {
+		records, _, err := listAllRecords(ctx, api, RecordListOpts{ZoneID: zone.ID})
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		result[zone.ID] = records
+		mu.Unlock()
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,210 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+func TestClientForEachZone(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	for i := 0; i < 20; i++ {
+		api.AddZone(fmt.Sprintf("zone%d.com", i), 3600)
+	}
+
+	var running, maxRunning int32
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	err := env.Client.ForEachZone(env.Context, 4, func(ctx context.Context, zone *Zone) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			prev := atomic.LoadInt32(&maxRunning)
+			if n <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		seen[zone.Name] = true
+		mu.Unlock()
+
+		if zone.Name == "zone7.com" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	var zoneErrs ZoneErrors
+	if !errors.As(err, &zoneErrs) || !as.EqInt(1, len(zoneErrs)) {
+		t.Fatalf("expected zone errors but got %v", err)
+	}
+	as.EqStr("zone7.com", zoneErrs[0].Zone.Name)
+	as.EqInt(20, len(seen))
+	if maxRunning > 4 || maxRunning < 2 {
+		t.Errorf("expected up to 4 concurrent calls but got %d", maxRunning)
+	}
+}
+
+func TestClientForEachZoneCanceled(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	for i := 0; i < 10; i++ {
+		api.AddZone(fmt.Sprintf("zone%d.com", i), 3600)
+	}
+
+	ctx, cancel := context.WithCancel(env.Context)
+	defer cancel()
+
+	var calls int32
+	err := env.Client.ForEachZone(ctx, 2, func(ctx context.Context, zone *Zone) error {
+		if atomic.AddInt32(&calls, 1) == 2 {
+			cancel()
+		}
+		return nil
+	})
+
+	var zoneErrs ZoneErrors
+	if !errors.As(err, &zoneErrs) {
+		t.Fatalf("expected zone errors but got %v", err)
+	}
+	as.EqInt(10, len(zoneErrs)+int(atomic.LoadInt32(&calls)))
+	if !errors.Is(zoneErrs[len(zoneErrs)-1], context.Canceled) {
+		t.Errorf("expected canceled error but got %v", zoneErrs[len(zoneErrs)-1])
+	}
+}
+
+func TestRecordClientListForZones(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	env.Mux.HandleFunc(pathRecords, func(w http.ResponseWriter, r *http.Request) {
+		zoneID := r.URL.Query().Get("zone_id")
+		if zoneID == "bad" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(schema.RecordListResponse{Records: []schema.Record{ // nolint: errcheck
+			{ID: zoneID + "-1", ZoneID: zoneID, Name: "www", Type: "A", Value: "192.0.2.1"},
+		}})
+	})
+
+	zones := []*Zone{{ID: "a", Name: "a.com"}, {ID: "bad", Name: "bad.com"}, {ID: "c", Name: "c.com"}}
+	records, err := env.Client.Record.ListForZones(env.Context, zones, 2)
+
+	var zoneErrs ZoneErrors
+	if !errors.As(err, &zoneErrs) || !as.EqInt(1, len(zoneErrs)) {
+		t.Fatalf("expected zone errors but got %v", err)
+	}
+	as.EqStr("bad", zoneErrs[0].Zone.ID)
+	as.EqInt(2, len(records))
+	if as.EqInt(1, len(records["c"])) {
+		as.EqStr("c-1", records["c"][0].ID)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc(pathZones, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.ZoneListResponse{}) // nolint: errcheck
+	})
+
+	client := NewClient(
+		WithEndpoint(env.Server.URL),
+		WithToken("32CharactersTokenxxxxxxxXxxxxxxx"),
+		WithRateLimit(50, 2),
+	)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := client.Zone.List(env.Context, ZoneListOpts{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The burst of 2 is sent immediately, the other 4 requests 20ms apart.
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("expected rate limited requests but took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(env.Context)
+	cancel()
+	if _, _, err := client.Zone.List(ctx, ZoneListOpts{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled error but got %v", err)
+	}
+}
+
+func TestRateLimiterCanceledWait(t *testing.T) {
+	as := newAssert(t)
+
+	limiter := newRateLimiter(10, 1)
+	as.NoError(limiter.wait(context.Background()))
+
+	// Canceled waits don't use up the slots of later requests.
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		as.Error(limiter.wait(ctx))
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	as.NoError(limiter.wait(ctx))
+}
+
+func TestClientRateLimitRequestTimeout(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc(pathZones, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.ZoneListResponse{}) // nolint: errcheck
+	})
+
+	// Queued requests wait longer for their slot than the request timeout.
+	client := NewClient(
+		WithEndpoint(env.Server.URL),
+		WithRateLimit(20, 1),
+		WithRequestTimeout(30*time.Millisecond),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := client.Zone.List(context.Background(), ZoneListOpts{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,5 +1,7 @@
 package dns
 
+import "time"
+
 // Version of the SDK
 const Version = "v0.1.0"
 
@@ -8,6 +10,15 @@ const Endpoint = "https://dns.hetzner.com/api/v1"
 
 const UserAgent = "hetzner-dns/" + Version
 
+// Default timeouts of the client.
+const (
+	DefaultConnectTimeout        = 10 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 60 * time.Second
+	DefaultRequestTimeout        = 60 * time.Second
+	DefaultTransferTimeout       = 5 * time.Minute
+)
+
 const (
 	pathZones          = "/zones"
 	pathRecords        = "/records"
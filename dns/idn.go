@@ -0,0 +1,43 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidName is returned for zone and record names which fail the
+// IDNA 2008 rules.
+var ErrInvalidName = errors.New("hetzner-dns: invalid name")
+
+// asciiName converts a zone or record name to the A-label form expected by
+// the API.
+func asciiName(name string) (string, error) {
+	ascii, err := ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidName, name, err)
+	}
+
+	return ascii, nil
+}
+
+// unicodeName returns the Unicode form of a name, or the name itself when
+// it contains invalid A-labels.
+func unicodeName(name string) string {
+	if unicode, err := ToUnicode(name); err == nil {
+		return unicode
+	}
+
+	return name
+}
+
+// UnicodeName returns the name of the zone in its Unicode form for display,
+// e.g. "münchen.de" for "xn--mnchen-3ya.de".
+func (z *Zone) UnicodeName() string {
+	return unicodeName(z.Name)
+}
+
+// UnicodeName returns the name of the record in its Unicode form for
+// display.
+func (r *Record) UnicodeName() string {
+	return unicodeName(r.Name)
+}
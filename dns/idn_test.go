@@ -0,0 +1,95 @@
+package dns
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+func TestZoneClientCreateIDN(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+
+	zone, _, err := env.Client.Zone.Create(env.Context, ZoneCreateOpts{Name: "Bücher-München.de"})
+	if !as.NoError(err) {
+		return
+	}
+	as.EqStr("xn--bcher-mnchen-dlbg.de", api.Zones[zone.ID].Name)
+	as.EqStr("bücher-münchen.de", zone.UnicodeName())
+
+	_, _, err = env.Client.Zone.Create(env.Context, ZoneCreateOpts{Name: "xn--a.de"})
+	if !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected invalid name error but got %v", err)
+	}
+
+	_, _, err = env.Client.Zone.Update(env.Context, zone, ZoneUpdateOpts{Name: "a‍b.de"})
+	if !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected invalid name error but got %v", err)
+	}
+}
+
+func TestRecordClientCreateIDN(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("xn--mnchen-3ya.de", 3600)
+	zone := &Zone{ID: zoneID}
+
+	rec, _, err := env.Client.Record.Create(env.Context, RecordCreateOpts{
+		Name: "straße", Type: RecordTypeA, Value: "192.0.2.1", Zone: zone,
+	})
+	if !as.NoError(err) {
+		return
+	}
+	as.EqStr("xn--strae-oqa", api.Records[rec.ID].Name)
+	as.EqStr("straße", rec.UnicodeName())
+
+	_, _, err = env.Client.Record.BulkCreate(env.Context, []RecordCreateOpts{
+		{Name: "_dmarc", Type: RecordTypeTXT, Value: "v=DMARC1; p=none", Zone: zone},
+		{Name: "*.bücher", Type: RecordTypeA, Value: "192.0.2.1", Zone: zone},
+	})
+	if as.NoError(err) {
+		records := api.ZoneRecords(zoneID)
+		as.EqStr("_dmarc", records[1].Name)
+		as.EqStr("*.xn--bcher-kva", records[2].Name)
+	}
+
+	_, _, err = env.Client.Record.Update(env.Context, rec, RecordUpdateOpts{
+		Name: "a‍b", Type: RecordTypeA, Value: "192.0.2.1", Zone: zone,
+	})
+	if !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected invalid name error but got %v", err)
+	}
+}
+
+func TestZoneClientListIDN(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	env.Mux.HandleFunc(pathZones, func(w http.ResponseWriter, r *http.Request) {
+		if name := r.URL.Query().Get("name"); name != "" {
+			as.EqStr("xn--mnchen-3ya.de", name)
+		} else {
+			as.EqStr("xn--mnchen-3ya", r.URL.Query().Get("search_name"))
+		}
+		json.NewEncoder(w).Encode(schema.ZoneListResponse{}) // nolint: errcheck
+	})
+
+	_, _, err := env.Client.Zone.List(env.Context, ZoneListOpts{Name: "münchen.de"})
+	as.NoError(err)
+
+	_, _, err = env.Client.Zone.List(env.Context, ZoneListOpts{SearchName: "München"})
+	as.NoError(err)
+}
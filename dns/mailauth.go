@@ -0,0 +1,828 @@
+package dns
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// MaxSPFLookups is the maximum number of DNS lookups an SPF evaluation may
+// cause, see RFC 7208 section 4.6.4.
+const MaxSPFLookups = 10
+
+// SPFQualifier is the result of an SPF mechanism when it matches.
+type SPFQualifier string
+
+const (
+	SPFPass     SPFQualifier = "+"
+	SPFFail     SPFQualifier = "-"
+	SPFSoftFail SPFQualifier = "~"
+	SPFNeutral  SPFQualifier = "?"
+)
+
+// SPFMechanismKind is the kind of an SPF mechanism.
+type SPFMechanismKind string
+
+const (
+	SPFAll     SPFMechanismKind = "all"
+	SPFInclude SPFMechanismKind = "include"
+	SPFA       SPFMechanismKind = "a"
+	SPFMX      SPFMechanismKind = "mx"
+	SPFPTR     SPFMechanismKind = "ptr"
+	SPFIP4     SPFMechanismKind = "ip4"
+	SPFIP6     SPFMechanismKind = "ip6"
+	SPFExists  SPFMechanismKind = "exists"
+)
+
+// SPFMechanism is a mechanism of an SPF record, e.g. "~all" or
+// "include:_spf.google.com".
+type SPFMechanism struct {
+	// Qualifier is empty for the default qualifier, which is SPFPass.
+	Qualifier SPFQualifier
+	Kind      SPFMechanismKind
+	// Value is the domain, address or network of the mechanism, optionally
+	// followed by a prefix length, e.g. "example.com/24" or "/24".
+	Value string
+}
+
+// String returns the mechanism as written in an SPF record.
+func (m SPFMechanism) String() string {
+	s := string(m.Qualifier) + string(m.Kind)
+	if m.Value != "" && !strings.HasPrefix(m.Value, "/") {
+		s += ":"
+	}
+
+	return s + m.Value
+}
+
+// lookups reports whether the mechanism causes a DNS lookup.
+func (m SPFMechanism) lookups() bool {
+	switch m.Kind {
+	case SPFInclude, SPFA, SPFMX, SPFPTR, SPFExists:
+		return true
+	}
+
+	return false
+}
+
+func (m SPFMechanism) validate() error {
+	switch m.Qualifier {
+	case "", SPFPass, SPFFail, SPFSoftFail, SPFNeutral:
+	default:
+		return fmt.Errorf("invalid qualifier %s", m.Qualifier)
+	}
+
+	switch m.Kind {
+	case SPFAll:
+		if m.Value != "" {
+			return errors.New("all takes no value")
+		}
+	case SPFInclude, SPFExists:
+		if m.Value == "" || strings.Contains(m.Value, "/") {
+			return fmt.Errorf("%s requires a domain", m.Kind)
+		}
+	case SPFA, SPFMX, SPFPTR:
+	case SPFIP4, SPFIP6:
+		ip, _, network := strings.Cut(m.Value, "/")
+		parsed := net.ParseIP(ip)
+		ip4 := parsed != nil && !strings.Contains(ip, ":")
+		if parsed == nil || ip4 != (m.Kind == SPFIP4) {
+			return fmt.Errorf("invalid %s address %s", m.Kind, m.Value)
+		}
+		if _, _, err := net.ParseCIDR(m.Value); network && err != nil {
+			return fmt.Errorf("invalid %s network %s", m.Kind, m.Value)
+		}
+	default:
+		return fmt.Errorf("unknown mechanism %s", m.Kind)
+	}
+
+	return nil
+}
+
+// SPF is a sender policy framework record, see RFC 7208.
+type SPF struct {
+	Mechanisms []SPFMechanism
+	// Redirect is the domain whose policy applies when no mechanism matches.
+	Redirect string
+	// Exp is the domain of the explanation of failures.
+	Exp string
+	// Modifiers holds the unknown modifiers, e.g. "ra=postmaster", which are
+	// kept as is.
+	Modifiers []string
+}
+
+// ParseSPF parses the value of an SPF record.
+func ParseSPF(value string) (*SPF, error) {
+	terms := strings.Fields(value)
+	if len(terms) == 0 || !strings.EqualFold(terms[0], "v=spf1") {
+		return nil, errors.New("spf: missing version v=spf1")
+	}
+
+	spf := &SPF{}
+	for _, term := range terms[1:] {
+		if name, v, ok := strings.Cut(term, "="); ok && !strings.ContainsAny(name, ":/") {
+			switch strings.ToLower(name) {
+			case "redirect":
+				spf.Redirect = v
+			case "exp":
+				spf.Exp = v
+			default:
+				spf.Modifiers = append(spf.Modifiers, term)
+			}
+			continue
+		}
+
+		var m SPFMechanism
+		if strings.ContainsAny(term[:1], "+-~?") {
+			m.Qualifier = SPFQualifier(term[:1])
+			term = term[1:]
+		}
+		kind := term
+		if i := strings.IndexAny(term, ":/"); i >= 0 {
+			kind = term[:i]
+			m.Value = strings.TrimPrefix(term[i:], ":")
+		}
+		m.Kind = SPFMechanismKind(strings.ToLower(kind))
+
+		if err := m.validate(); err != nil {
+			return nil, fmt.Errorf("spf: %w", err)
+		}
+		spf.Mechanisms = append(spf.Mechanisms, m)
+	}
+
+	return spf, nil
+}
+
+// String returns the value of the SPF record.
+func (s *SPF) String() string {
+	terms := []string{"v=spf1"}
+	for _, m := range s.Mechanisms {
+		terms = append(terms, m.String())
+	}
+	if s.Redirect != "" {
+		terms = append(terms, "redirect="+s.Redirect)
+	}
+	if s.Exp != "" {
+		terms = append(terms, "exp="+s.Exp)
+	}
+	terms = append(terms, s.Modifiers...)
+
+	return strings.Join(terms, " ")
+}
+
+// Lookups returns the number of DNS lookups of the mechanisms and the
+// redirect of the record, not counting the lookups of included records.
+func (s *SPF) Lookups() int {
+	n := 0
+	for _, m := range s.Mechanisms {
+		if m.lookups() {
+			n++
+		}
+	}
+	if s.Redirect != "" {
+		n++
+	}
+
+	return n
+}
+
+// Validate checks if the record is valid and causes at most MaxSPFLookups
+// lookups on its own.
+func (s *SPF) Validate() error {
+	for i, m := range s.Mechanisms {
+		if err := m.validate(); err != nil {
+			return fmt.Errorf("spf: %w", err)
+		}
+		if m.Kind == SPFAll && i < len(s.Mechanisms)-1 {
+			return errors.New("spf: mechanisms after all are never evaluated")
+		}
+	}
+	if n := s.Lookups(); n > MaxSPFLookups {
+		return fmt.Errorf("spf: %d lookups exceed the limit of %d", n, MaxSPFLookups)
+	}
+
+	return nil
+}
+
+// CountLookups returns the number of DNS lookups of the record including the
+// lookups of the included and redirected records, which are resolved with r.
+// Domains containing macros are not resolved.
+func (s *SPF) CountLookups(ctx context.Context, r TXTResolver) (int, error) {
+	return s.countLookups(ctx, r, map[string]bool{})
+}
+
+func (s *SPF) countLookups(ctx context.Context, r TXTResolver, seen map[string]bool) (int, error) {
+	n := 0
+	var targets []string
+	for _, m := range s.Mechanisms {
+		if !m.lookups() {
+			continue
+		}
+		n++
+		if m.Kind == SPFInclude {
+			targets = append(targets, m.Value)
+		}
+	}
+	if s.Redirect != "" {
+		n++
+		targets = append(targets, s.Redirect)
+	}
+
+	for _, target := range targets {
+		if strings.Contains(target, "%") {
+			continue
+		}
+
+		key := FoldName(strings.TrimSuffix(target, "."))
+		if seen[key] {
+			return 0, fmt.Errorf("spf: include loop at %s", target)
+		}
+		seen[key] = true
+
+		included, err := lookupSPF(ctx, r, target)
+		if err != nil {
+			return 0, err
+		}
+		count, err := included.countLookups(ctx, r, seen)
+		if err != nil {
+			return 0, err
+		}
+		n += count
+		delete(seen, key)
+	}
+
+	return n, nil
+}
+
+// lookupSPF resolves the SPF record of domain.
+func lookupSPF(ctx context.Context, r TXTResolver, domain string) (*SPF, error) {
+	values, err := r.LookupTXT(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("spf: %s: %w", domain, err)
+	}
+
+	var found *SPF
+	for _, value := range values {
+		if !hasTagPrefix(value, "v=spf1") {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("spf: %s: multiple records", domain)
+		}
+		if found, err = ParseSPF(value); err != nil {
+			return nil, fmt.Errorf("spf: %s: %w", domain, err)
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("spf: %s: no record", domain)
+	}
+
+	return found, nil
+}
+
+// RecordCreateOpts returns the options to create the record with the given
+// name in the zone, "@" for the zone apex.
+func (s *SPF) RecordCreateOpts(zone *Zone, name string) (RecordCreateOpts, error) {
+	if err := s.Validate(); err != nil {
+		return RecordCreateOpts{}, err
+	}
+
+	return txtRecordCreateOpts(zone, name, s.String()), nil
+}
+
+// txtRecordCreateOpts returns the options to create a TXT record.
+func txtRecordCreateOpts(zone *Zone, name, value string) RecordCreateOpts {
+	return RecordCreateOpts{
+		Name:  name,
+		Type:  RecordTypeTXT,
+		Value: value,
+		Zone:  zone,
+	}
+}
+
+// hasTagPrefix reports whether the record value starts with the version tag,
+// e.g. "v=DMARC1", regardless of case.
+func hasTagPrefix(value, version string) bool {
+	value = strings.TrimSpace(value)
+	if len(value) < len(version) || !strings.EqualFold(value[:len(version)], version) {
+		return false
+	}
+	rest := value[len(version):]
+
+	return rest == "" || strings.ContainsAny(rest[:1], " ;\t")
+}
+
+// tag is a tag of a tag list record like DKIM and DMARC.
+type tag struct {
+	Name  string
+	Value string
+}
+
+// parseTags parses a tag list "name=value; name=value", see RFC 6376
+// section 3.2.
+func parseTags(s string) ([]tag, error) {
+	var tags []tag
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q", part)
+		}
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate tag %s", name)
+		}
+		seen[name] = true
+		tags = append(tags, tag{Name: name, Value: strings.TrimSpace(value)})
+	}
+
+	return tags, nil
+}
+
+// formatTags formats tags as tag list, leaving out empty values.
+func formatTags(tags []tag) string {
+	parts := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t.Value != "" {
+			parts = append(parts, t.Name+"="+t.Value)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// splitList splits a colon separated tag value.
+func splitList(value, sep string) []string {
+	var list []string
+	for _, v := range strings.Split(value, sep) {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// DKIM is a DKIM public key record, see RFC 6376 section 3.6.1.
+type DKIM struct {
+	// KeyType is "rsa" or "ed25519", empty for the default rsa.
+	KeyType string
+	// PublicKey is the base64 encoded public key, empty when the key was
+	// revoked.
+	PublicKey string
+	// HashAlgorithms, ServiceTypes and Flags are the h, s and t tags.
+	HashAlgorithms []string
+	ServiceTypes   []string
+	Flags          []string
+	Notes          string
+}
+
+// NewDKIM returns the record of an RSA or Ed25519 public key.
+func NewDKIM(key crypto.PublicKey) (*DKIM, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			return nil, err
+		}
+		return &DKIM{KeyType: "rsa", PublicKey: base64.StdEncoding.EncodeToString(der)}, nil
+	case ed25519.PublicKey:
+		return &DKIM{KeyType: "ed25519", PublicKey: base64.StdEncoding.EncodeToString(k)}, nil
+	}
+
+	return nil, fmt.Errorf("dkim: unsupported key type %T", key)
+}
+
+// ParseDKIM parses the value of a DKIM public key record.
+func ParseDKIM(value string) (*DKIM, error) {
+	tags, err := parseTags(value)
+	if err != nil {
+		return nil, fmt.Errorf("dkim: %w", err)
+	}
+
+	d := &DKIM{}
+	hasKey := false
+	for i, t := range tags {
+		switch t.Name {
+		case "v":
+			if i != 0 || t.Value != "DKIM1" {
+				return nil, errors.New("dkim: version must be the first tag v=DKIM1")
+			}
+		case "k":
+			d.KeyType = t.Value
+		case "p":
+			d.PublicKey = strings.Join(strings.Fields(t.Value), "")
+			hasKey = true
+		case "h":
+			d.HashAlgorithms = splitList(t.Value, ":")
+		case "s":
+			d.ServiceTypes = splitList(t.Value, ":")
+		case "t":
+			d.Flags = splitList(t.Value, ":")
+		case "n":
+			d.Notes = t.Value
+		}
+	}
+	if !hasKey {
+		return nil, errors.New("dkim: missing public key p=")
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// String returns the value of the DKIM record.
+func (d *DKIM) String() string {
+	tags := []tag{
+		{"v", "DKIM1"},
+		{"k", d.KeyType},
+		{"h", strings.Join(d.HashAlgorithms, ":")},
+		{"s", strings.Join(d.ServiceTypes, ":")},
+		{"t", strings.Join(d.Flags, ":")},
+		{"n", d.Notes},
+	}
+
+	// The key is written even when empty, which revokes it.
+	return formatTags(tags) + "; p=" + d.PublicKey
+}
+
+// Revoked reports whether the key was revoked.
+func (d *DKIM) Revoked() bool {
+	return d.PublicKey == ""
+}
+
+// KeyBits returns the size of an RSA key in bits, or 0 for other keys.
+func (d *DKIM) KeyBits() int {
+	if d.KeyType != "" && d.KeyType != "rsa" {
+		return 0
+	}
+
+	der, err := base64.StdEncoding.DecodeString(d.PublicKey)
+	if err != nil {
+		return 0
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		// Some signers publish PKCS #1 keys.
+		if key, err = x509.ParsePKCS1PublicKey(der); err != nil {
+			return 0
+		}
+	}
+	if rsaKey, ok := key.(*rsa.PublicKey); ok {
+		return rsaKey.N.BitLen()
+	}
+
+	return 0
+}
+
+// Validate checks if the key type is known and the public key is a valid key
+// of its type.
+func (d *DKIM) Validate() error {
+	if d.Revoked() {
+		return nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(d.PublicKey)
+	if err != nil {
+		return fmt.Errorf("dkim: invalid public key: %w", err)
+	}
+
+	switch d.KeyType {
+	case "", "rsa":
+		if d.KeyBits() == 0 {
+			return errors.New("dkim: invalid rsa public key")
+		}
+	case "ed25519":
+		if len(der) != ed25519.PublicKeySize {
+			return errors.New("dkim: invalid ed25519 public key")
+		}
+	default:
+		return fmt.Errorf("dkim: unknown key type %s", d.KeyType)
+	}
+
+	return nil
+}
+
+// RecordCreateOpts returns the options to create the record of the selector
+// in the zone.
+func (d *DKIM) RecordCreateOpts(zone *Zone, selector string) (RecordCreateOpts, error) {
+	if selector == "" {
+		return RecordCreateOpts{}, errors.New("dkim: selector required")
+	}
+	if err := d.Validate(); err != nil {
+		return RecordCreateOpts{}, err
+	}
+
+	return txtRecordCreateOpts(zone, selector+"._domainkey", d.String()), nil
+}
+
+// DMARCPolicy is the requested handling of mail failing DMARC.
+type DMARCPolicy string
+
+const (
+	DMARCNone       DMARCPolicy = "none"
+	DMARCQuarantine DMARCPolicy = "quarantine"
+	DMARCReject     DMARCPolicy = "reject"
+)
+
+func (p DMARCPolicy) valid() bool {
+	return p == DMARCNone || p == DMARCQuarantine || p == DMARCReject
+}
+
+// DMARC is a DMARC policy record, see RFC 7489 section 6.3.
+type DMARC struct {
+	Policy DMARCPolicy
+	// SubdomainPolicy is the policy of subdomains, empty for Policy.
+	SubdomainPolicy DMARCPolicy
+	// Percent is the percentage of mail the policy applies to, nil for 100.
+	Percent *int
+	// AggregateReports and FailureReports are the URIs reports are sent to,
+	// e.g. "mailto:dmarc@example.com".
+	AggregateReports []string
+	FailureReports   []string
+	// DKIMAlignment and SPFAlignment are "r" for relaxed or "s" for strict,
+	// empty for relaxed.
+	DKIMAlignment string
+	SPFAlignment  string
+	// FailureOptions is the fo tag, e.g. "1".
+	FailureOptions string
+	// ReportInterval is the interval of aggregate reports in seconds, nil for
+	// a day.
+	ReportInterval *int
+}
+
+// ParseDMARC parses the value of a DMARC record.
+func ParseDMARC(value string) (*DMARC, error) {
+	tags, err := parseTags(value)
+	if err != nil {
+		return nil, fmt.Errorf("dmarc: %w", err)
+	}
+	if len(tags) == 0 || tags[0].Name != "v" || tags[0].Value != "DMARC1" {
+		return nil, errors.New("dmarc: version must be the first tag v=DMARC1")
+	}
+
+	d := &DMARC{}
+	for _, t := range tags[1:] {
+		switch t.Name {
+		case "p":
+			d.Policy = DMARCPolicy(strings.ToLower(t.Value))
+		case "sp":
+			d.SubdomainPolicy = DMARCPolicy(strings.ToLower(t.Value))
+		case "pct":
+			pct, err := strconv.Atoi(t.Value)
+			if err != nil {
+				return nil, fmt.Errorf("dmarc: invalid pct %s", t.Value)
+			}
+			d.Percent = &pct
+		case "rua":
+			d.AggregateReports = splitList(t.Value, ",")
+		case "ruf":
+			d.FailureReports = splitList(t.Value, ",")
+		case "adkim":
+			d.DKIMAlignment = t.Value
+		case "aspf":
+			d.SPFAlignment = t.Value
+		case "fo":
+			d.FailureOptions = t.Value
+		case "ri":
+			ri, err := strconv.Atoi(t.Value)
+			if err != nil {
+				return nil, fmt.Errorf("dmarc: invalid ri %s", t.Value)
+			}
+			d.ReportInterval = &ri
+		}
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// String returns the value of the DMARC record.
+func (d *DMARC) String() string {
+	tags := []tag{
+		{"v", "DMARC1"},
+		{"p", string(d.Policy)},
+		{"sp", string(d.SubdomainPolicy)},
+		{"adkim", d.DKIMAlignment},
+		{"aspf", d.SPFAlignment},
+		{"rua", strings.Join(d.AggregateReports, ",")},
+		{"ruf", strings.Join(d.FailureReports, ",")},
+		{"fo", d.FailureOptions},
+	}
+	if d.Percent != nil {
+		tags = append(tags, tag{"pct", strconv.Itoa(*d.Percent)})
+	}
+	if d.ReportInterval != nil {
+		tags = append(tags, tag{"ri", strconv.Itoa(*d.ReportInterval)})
+	}
+
+	return formatTags(tags)
+}
+
+// Validate checks if the policy is valid.
+func (d *DMARC) Validate() error {
+	if !d.Policy.valid() {
+		return fmt.Errorf("dmarc: invalid policy %q", d.Policy)
+	}
+	if d.SubdomainPolicy != "" && !d.SubdomainPolicy.valid() {
+		return fmt.Errorf("dmarc: invalid subdomain policy %q", d.SubdomainPolicy)
+	}
+	if d.Percent != nil && (*d.Percent < 0 || *d.Percent > 100) {
+		return fmt.Errorf("dmarc: invalid pct %d", *d.Percent)
+	}
+	for _, a := range []string{d.DKIMAlignment, d.SPFAlignment} {
+		if a != "" && a != "r" && a != "s" {
+			return fmt.Errorf("dmarc: invalid alignment %q", a)
+		}
+	}
+	for _, uri := range append(append([]string(nil), d.AggregateReports...), d.FailureReports...) {
+		if err := validateReportURI(uri); err != nil {
+			return fmt.Errorf("dmarc: %w", err)
+		}
+	}
+	if d.ReportInterval != nil && *d.ReportInterval < 0 {
+		return fmt.Errorf("dmarc: invalid ri %d", *d.ReportInterval)
+	}
+
+	return nil
+}
+
+// validateReportURI checks if uri is a mailto or https report URI.
+func validateReportURI(uri string) error {
+	lower := strings.ToLower(uri)
+	switch {
+	case strings.HasPrefix(lower, "mailto:") && strings.Contains(uri, "@"):
+		return nil
+	case strings.HasPrefix(lower, "https://") && len(uri) > len("https://"):
+		return nil
+	}
+
+	return fmt.Errorf("invalid report uri %q", uri)
+}
+
+// RecordCreateOpts returns the options to create the record of the zone.
+func (d *DMARC) RecordCreateOpts(zone *Zone) (RecordCreateOpts, error) {
+	if err := d.Validate(); err != nil {
+		return RecordCreateOpts{}, err
+	}
+
+	return txtRecordCreateOpts(zone, "_dmarc", d.String()), nil
+}
+
+// MTASTS is the TXT record announcing an MTA-STS policy, see RFC 8461
+// section 3.1. The policy itself is served at
+// https://mta-sts.<domain>/.well-known/mta-sts.txt, see MTASTSPolicy.
+type MTASTS struct {
+	// ID identifies the version of the policy and must change with the
+	// policy, e.g. "20240101T000000".
+	ID string
+}
+
+// ParseMTASTS parses the value of an MTA-STS record.
+func ParseMTASTS(value string) (*MTASTS, error) {
+	tags, err := parseTags(value)
+	if err != nil {
+		return nil, fmt.Errorf("mta-sts: %w", err)
+	}
+	if len(tags) == 0 || tags[0].Name != "v" || tags[0].Value != "STSv1" {
+		return nil, errors.New("mta-sts: version must be the first tag v=STSv1")
+	}
+
+	m := &MTASTS{}
+	for _, t := range tags[1:] {
+		if t.Name == "id" {
+			m.ID = t.Value
+		}
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// String returns the value of the MTA-STS record.
+func (m *MTASTS) String() string {
+	return formatTags([]tag{{"v", "STSv1"}, {"id", m.ID}}) + ";"
+}
+
+// Validate checks if the id consists of 1 to 32 letters and digits.
+func (m *MTASTS) Validate() error {
+	if m.ID == "" || len(m.ID) > 32 {
+		return errors.New("mta-sts: id must have 1 to 32 characters")
+	}
+	for _, r := range m.ID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("mta-sts: invalid id %s", m.ID)
+		}
+	}
+
+	return nil
+}
+
+// RecordCreateOpts returns the options to create the record of the zone.
+func (m *MTASTS) RecordCreateOpts(zone *Zone) (RecordCreateOpts, error) {
+	if err := m.Validate(); err != nil {
+		return RecordCreateOpts{}, err
+	}
+
+	return txtRecordCreateOpts(zone, "_mta-sts", m.String()), nil
+}
+
+// MTASTSPolicy is an MTA-STS policy file, see RFC 8461 section 3.2.
+type MTASTSPolicy struct {
+	// Mode is "enforce", "testing" or "none".
+	Mode string
+	// MX holds the patterns of the allowed mail servers, e.g. "*.example.com".
+	MX []string
+	// MaxAge is the time in seconds the policy may be cached.
+	MaxAge int
+}
+
+// String returns the policy file.
+func (p *MTASTSPolicy) String() string {
+	var b strings.Builder
+	b.WriteString("version: STSv1\r\n")
+	fmt.Fprintf(&b, "mode: %s\r\n", p.Mode)
+	for _, mx := range p.MX {
+		fmt.Fprintf(&b, "mx: %s\r\n", mx)
+	}
+	fmt.Fprintf(&b, "max_age: %d\r\n", p.MaxAge)
+
+	return b.String()
+}
+
+// TLSRPT is an SMTP TLS reporting record, see RFC 8460 section 3.
+type TLSRPT struct {
+	// Reports holds the URIs reports are sent to, e.g.
+	// "mailto:tlsrpt@example.com".
+	Reports []string
+}
+
+// ParseTLSRPT parses the value of a TLS-RPT record.
+func ParseTLSRPT(value string) (*TLSRPT, error) {
+	tags, err := parseTags(value)
+	if err != nil {
+		return nil, fmt.Errorf("tls-rpt: %w", err)
+	}
+	if len(tags) == 0 || tags[0].Name != "v" || tags[0].Value != "TLSRPTv1" {
+		return nil, errors.New("tls-rpt: version must be the first tag v=TLSRPTv1")
+	}
+
+	t := &TLSRPT{}
+	for _, tg := range tags[1:] {
+		if tg.Name == "rua" {
+			t.Reports = splitList(tg.Value, ",")
+		}
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// String returns the value of the TLS-RPT record.
+func (t *TLSRPT) String() string {
+	return formatTags([]tag{{"v", "TLSRPTv1"}, {"rua", strings.Join(t.Reports, ",")}})
+}
+
+// Validate checks if there is at least one valid report URI.
+func (t *TLSRPT) Validate() error {
+	if len(t.Reports) == 0 {
+		return errors.New("tls-rpt: report uri required")
+	}
+	for _, uri := range t.Reports {
+		if err := validateReportURI(uri); err != nil {
+			return fmt.Errorf("tls-rpt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RecordCreateOpts returns the options to create the record of the zone.
+func (t *TLSRPT) RecordCreateOpts(zone *Zone) (RecordCreateOpts, error) {
+	if err := t.Validate(); err != nil {
+		return RecordCreateOpts{}, err
+	}
+
+	return txtRecordCreateOpts(zone, "_smtp._tls", t.String()), nil
+}
@@ -0,0 +1,203 @@
+package dns
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSPF(t *testing.T) {
+	as := newAssert(t)
+
+	value := "v=spf1 mx a:mail.hetzner.com/28 ip4:192.0.2.0/24 ip6:2001:db8::1 include:_spf.google.com -all exp=explain.hetzner.com ra=postmaster"
+	spf, err := ParseSPF(value)
+	if !as.NoError(err) {
+		return
+	}
+	as.EqInt(6, len(spf.Mechanisms))
+	as.EqStr("mail.hetzner.com/28", spf.Mechanisms[1].Value)
+	as.EqStr(string(SPFFail), string(spf.Mechanisms[5].Qualifier))
+	as.EqStr("explain.hetzner.com", spf.Exp)
+	as.EqInt(3, spf.Lookups())
+	as.EqStr(value, spf.String())
+	as.NoError(spf.Validate())
+
+	for _, invalid := range []string{
+		"spf1 -all",
+		"v=spf1 ip4:2001:db8::1",
+		"v=spf1 ip6:192.0.2.1",
+		"v=spf1 ip4:192.0.2.1/33",
+		"v=spf1 include",
+		"v=spf1 foo:bar",
+	} {
+		if _, err := ParseSPF(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+
+	spf, _ = ParseSPF("v=spf1 -all mx")
+	as.Error(spf.Validate())
+
+	spf, _ = ParseSPF("v=spf1 " + strings.Repeat("a ", MaxSPFLookups+1) + "-all")
+	as.Error(spf.Validate())
+}
+
+type txtResolverFunc func(ctx context.Context, name string) ([]string, error)
+
+func (f txtResolverFunc) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return f(ctx, name)
+}
+
+func TestSPFCountLookups(t *testing.T) {
+	as := newAssert(t)
+
+	zone := map[string][]string{
+		"hetzner.com":       {"google-site-verification=x", "v=spf1 include:_spf.hetzner.com redirect=_spf2.hetzner.com"},
+		"_spf.hetzner.com":  {"v=spf1 a mx ip4:192.0.2.1 ~all"},
+		"_spf2.hetzner.com": {"v=spf1 include:_spf.hetzner.com exists:%{i}.hetzner.com -all"},
+		"loop.hetzner.com":  {"v=spf1 include:loop.hetzner.com -all"},
+	}
+	resolver := txtResolverFunc(func(ctx context.Context, name string) ([]string, error) {
+		values, ok := zone[name]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		return values, nil
+	})
+
+	spf, _ := ParseSPF(zone["hetzner.com"][1])
+	n, err := spf.CountLookups(context.Background(), resolver)
+	if as.NoError(err) {
+		// include, redirect, a, mx, include, a, mx and exists
+		as.EqInt(8, n)
+	}
+
+	spf, _ = ParseSPF(zone["loop.hetzner.com"][0])
+	_, err = spf.CountLookups(context.Background(), resolver)
+	as.Error(err)
+
+	spf, _ = ParseSPF("v=spf1 include:missing.hetzner.com -all")
+	_, err = spf.CountLookups(context.Background(), resolver)
+	as.Error(err)
+}
+
+func TestDKIM(t *testing.T) {
+	as := newAssert(t)
+	zone := &Zone{ID: "zone1", Name: "hetzner.com"}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDKIM(&key.PublicKey)
+	if !as.NoError(err) {
+		return
+	}
+	as.EqInt(2048, d.KeyBits())
+
+	opts, err := d.RecordCreateOpts(zone, "mail")
+	if !as.NoError(err) {
+		return
+	}
+	as.EqStr("mail._domainkey", opts.Name)
+	as.EqStr(string(RecordTypeTXT), string(opts.Type))
+
+	parsed, err := ParseDKIM(opts.Value)
+	if as.NoError(err) {
+		as.EqStr(d.PublicKey, parsed.PublicKey)
+		as.EqStr("rsa", parsed.KeyType)
+	}
+
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	d, err = NewDKIM(pub)
+	if as.NoError(err) {
+		_, err = ParseDKIM(d.String())
+		as.NoError(err)
+	}
+
+	revoked, err := ParseDKIM("v=DKIM1; p=")
+	if as.NoError(err) && !revoked.Revoked() {
+		t.Error("expected revoked key")
+	}
+
+	for _, invalid := range []string{
+		"v=DKIM1; k=rsa",
+		"v=DKIM1; p=bm90IGEga2V5",
+		"k=rsa; v=DKIM1; p=",
+		"v=DKIM1; k=dsa; p=bm90IGEga2V5",
+	} {
+		if _, err := ParseDKIM(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestDMARC(t *testing.T) {
+	as := newAssert(t)
+
+	pct := 50
+	d := &DMARC{
+		Policy:           DMARCQuarantine,
+		SubdomainPolicy:  DMARCReject,
+		Percent:          &pct,
+		AggregateReports: []string{"mailto:dmarc@hetzner.com"},
+		SPFAlignment:     "s",
+	}
+	opts, err := d.RecordCreateOpts(&Zone{Name: "hetzner.com"})
+	if !as.NoError(err) {
+		return
+	}
+	as.EqStr("_dmarc", opts.Name)
+	as.EqStr("v=DMARC1; p=quarantine; sp=reject; aspf=s; rua=mailto:dmarc@hetzner.com; pct=50", opts.Value)
+
+	parsed, err := ParseDMARC(opts.Value)
+	if as.NoError(err) {
+		as.EqStr(d.String(), parsed.String())
+	}
+
+	for _, invalid := range []string{
+		"p=reject; v=DMARC1",
+		"v=DMARC1",
+		"v=DMARC1; p=block",
+		"v=DMARC1; p=none; pct=101",
+		"v=DMARC1; p=none; rua=dmarc@hetzner.com",
+		"v=DMARC1; p=none; p=reject",
+	} {
+		if _, err := ParseDMARC(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestMTASTSAndTLSRPT(t *testing.T) {
+	as := newAssert(t)
+	zone := &Zone{Name: "hetzner.com"}
+
+	opts, err := (&MTASTS{ID: "20240101T000000"}).RecordCreateOpts(zone)
+	if as.NoError(err) {
+		as.EqStr("_mta-sts", opts.Name)
+		as.EqStr("v=STSv1; id=20240101T000000;", opts.Value)
+	}
+	_, err = (&MTASTS{ID: "2024-01-01"}).RecordCreateOpts(zone)
+	as.Error(err)
+
+	m, err := ParseMTASTS("v=STSv1; id=abc123")
+	if as.NoError(err) {
+		as.EqStr("abc123", m.ID)
+	}
+
+	policy := &MTASTSPolicy{Mode: "enforce", MX: []string{"mail.hetzner.com"}, MaxAge: 604800}
+	as.EqStr("version: STSv1\r\nmode: enforce\r\nmx: mail.hetzner.com\r\nmax_age: 604800\r\n", policy.String())
+
+	opts, err = (&TLSRPT{Reports: []string{"mailto:tlsrpt@hetzner.com", "https://report.hetzner.com/tls"}}).RecordCreateOpts(zone)
+	if as.NoError(err) {
+		as.EqStr("_smtp._tls", opts.Name)
+		as.EqStr("v=TLSRPTv1; rua=mailto:tlsrpt@hetzner.com,https://report.hetzner.com/tls", opts.Value)
+	}
+	_, err = ParseTLSRPT("v=TLSRPTv1")
+	as.Error(err)
+}
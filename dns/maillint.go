@@ -0,0 +1,260 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LintSeverity is the severity of a LintIssue.
+type LintSeverity string
+
+const (
+	// LintError is a misconfiguration which breaks the record.
+	LintError LintSeverity = "error"
+	// LintWarning is a configuration which works but is likely unintended
+	// or weak.
+	LintWarning LintSeverity = "warning"
+)
+
+// LintIssue is a misconfiguration found by LintMailAuth.
+type LintIssue struct {
+	// Name is the name of the record relative to the zone.
+	Name     string
+	Severity LintSeverity
+	Message  string
+}
+
+func (i *LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Name, i.Severity, i.Message)
+}
+
+// LintMailAuth lists the records of the zone and checks their email
+// authentication records, see LintMailAuth.
+func (c RecordClient) LintMailAuth(ctx context.Context, zone *Zone) ([]*LintIssue, *Response, error) {
+	return LintZoneMailAuth(ctx, c, zone)
+}
+
+// LintZoneMailAuth lists the records of the zone through api and checks
+// their email authentication records, see LintMailAuth.
+func LintZoneMailAuth(ctx context.Context, api RecordAPI, zone *Zone) ([]*LintIssue, *Response, error) {
+	records, resp, err := listAllRecords(ctx, api, RecordListOpts{ZoneID: zone.ID})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return LintMailAuth(zone, records), resp, nil
+}
+
+// LintMailAuth checks the SPF, DKIM, DMARC, MTA-STS and TLS-RPT records of
+// the zone. Besides invalid and duplicate records it reports weak policies,
+// like SPF records allowing all senders or DMARC policies without reports,
+// and names receiving mail without SPF record or, at the apex, without DMARC
+// record. The issues are ordered by name.
+func LintMailAuth(zone *Zone, records []*Record) []*LintIssue {
+	l := &mailLinter{
+		txt:   map[string][]string{},
+		mx:    map[string]bool{},
+		names: map[string]string{},
+	}
+	for _, rec := range records {
+		name := ToRelative(zone.Name, rec.Name)
+		key := FoldName(name)
+		l.names[key] = name
+
+		switch rec.Type {
+		case RecordTypeTXT:
+			l.txt[key] = append(l.txt[key], rec.Value)
+		case RecordTypeMX:
+			l.mx[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(l.names))
+	for key := range l.names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		l.lintName(key)
+	}
+
+	return l.issues
+}
+
+// mailLinter holds the records of a zone by folded name.
+type mailLinter struct {
+	txt    map[string][]string
+	mx     map[string]bool
+	names  map[string]string
+	issues []*LintIssue
+}
+
+func (l *mailLinter) report(key string, severity LintSeverity, format string, args ...interface{}) {
+	l.issues = append(l.issues, &LintIssue{
+		Name:     l.names[key],
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// records returns the TXT values of the name starting with the version tag.
+func (l *mailLinter) records(key, version string) []string {
+	var values []string
+	for _, value := range l.txt[key] {
+		if hasTagPrefix(value, version) {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// single returns the only value of the name starting with the version tag
+// and reports multiple values.
+func (l *mailLinter) single(key, version, kind string) (string, bool) {
+	values := l.records(key, version)
+	if len(values) > 1 {
+		l.report(key, LintError, "%d %s records, receivers treat this as no record", len(values), kind)
+		return "", false
+	}
+
+	return strings.Join(values, ""), len(values) == 1
+}
+
+func (l *mailLinter) lintName(key string) {
+	l.lintSPF(key)
+
+	labels := strings.Split(key, ".")
+	switch {
+	case labels[0] == "_dmarc":
+		l.lintDMARC(key)
+	case len(labels) > 1 && labels[1] == "_domainkey":
+		l.lintDKIM(key)
+	case labels[0] == "_mta-sts":
+		l.lintMTASTS(key)
+	case len(labels) > 1 && labels[0] == "_smtp" && labels[1] == "_tls":
+		l.lintTLSRPT(key)
+	}
+
+	if l.mx[key] {
+		if len(l.records(key, "v=spf1")) == 0 {
+			l.report(key, LintWarning, "receives mail but has no SPF record")
+		}
+		// Subdomains fall back to the policy of the apex.
+		if key == "@" && len(l.records("_dmarc", "v=DMARC1")) == 0 {
+			l.report(key, LintWarning, "receives mail but has no DMARC record")
+		}
+	}
+}
+
+// childKey returns the key of the label below the name of key.
+func childKey(label, key string) string {
+	if key == "@" {
+		return label
+	}
+
+	return label + "." + key
+}
+
+func (l *mailLinter) lintSPF(key string) {
+	value, ok := l.single(key, "v=spf1", "SPF")
+	if !ok {
+		return
+	}
+
+	spf, err := ParseSPF(value)
+	if err == nil {
+		err = spf.Validate()
+	}
+	if err != nil {
+		l.report(key, LintError, "%s", err)
+		return
+	}
+
+	hasAll := false
+	for _, m := range spf.Mechanisms {
+		switch m.Kind {
+		case SPFAll:
+			hasAll = true
+			if m.Qualifier == "" || m.Qualifier == SPFPass {
+				l.report(key, LintError, "spf: %s allows any sender", m)
+			}
+		case SPFPTR:
+			l.report(key, LintWarning, "spf: the ptr mechanism is deprecated")
+		}
+	}
+	if !hasAll && spf.Redirect == "" {
+		l.report(key, LintWarning, "spf: neither all nor redirect, other senders are neutral")
+	}
+}
+
+func (l *mailLinter) lintDMARC(key string) {
+	value, ok := l.single(key, "v=DMARC1", "DMARC")
+	if !ok {
+		return
+	}
+
+	dmarc, err := ParseDMARC(value)
+	if err != nil {
+		l.report(key, LintError, "%s", err)
+		return
+	}
+	if dmarc.Policy == DMARCNone {
+		l.report(key, LintWarning, "dmarc: policy none only monitors")
+	}
+	if dmarc.Percent != nil && *dmarc.Percent < 100 {
+		l.report(key, LintWarning, "dmarc: policy applies to %d%% of mail only", *dmarc.Percent)
+	}
+	if len(dmarc.AggregateReports) == 0 {
+		l.report(key, LintWarning, "dmarc: no aggregate reports requested")
+	}
+}
+
+func (l *mailLinter) lintDKIM(key string) {
+	for _, value := range l.txt[key] {
+		d, err := ParseDKIM(value)
+		switch {
+		case err != nil:
+			l.report(key, LintError, "%s", err)
+		case d.Revoked():
+			l.report(key, LintWarning, "dkim: key revoked")
+		case d.KeyBits() > 0 && d.KeyBits() < 1024:
+			l.report(key, LintError, "dkim: %d bit rsa key is too short", d.KeyBits())
+		case d.KeyBits() > 0 && d.KeyBits() < 2048:
+			l.report(key, LintWarning, "dkim: %d bit rsa key is weak, use at least 2048 bits", d.KeyBits())
+		}
+	}
+}
+
+func (l *mailLinter) lintMTASTS(key string) {
+	value, ok := l.single(key, "v=STSv1", "MTA-STS")
+	if !ok {
+		return
+	}
+
+	if _, err := ParseMTASTS(value); err != nil {
+		l.report(key, LintError, "%s", err)
+	}
+
+	domain := strings.TrimPrefix(strings.TrimPrefix(key, "_mta-sts"), ".")
+	if domain == "" {
+		domain = "@"
+	}
+	if len(l.records(childKey("_smtp._tls", domain), "v=TLSRPTv1")) == 0 {
+		l.report(key, LintWarning, "mta-sts: no TLS-RPT record to report failures")
+	}
+}
+
+func (l *mailLinter) lintTLSRPT(key string) {
+	value, ok := l.single(key, "v=TLSRPTv1", "TLS-RPT")
+	if !ok {
+		return
+	}
+
+	if _, err := ParseTLSRPT(value); err != nil {
+		l.report(key, LintError, "%s", err)
+	}
+}
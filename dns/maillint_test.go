@@ -0,0 +1,78 @@
+package dns
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestLintMailAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weak, _ := NewDKIM(&key.PublicKey)
+
+	zone := &Zone{ID: "zone1", Name: "hetzner.com"}
+	records := []*Record{
+		{Name: "@", Type: RecordTypeMX, Value: "10 mail.hetzner.com."},
+		{Name: "@", Type: RecordTypeTXT, Value: "v=spf1 mx +all"},
+		{Name: "mail._domainkey", Type: RecordTypeTXT, Value: weak.String()},
+		// Values are decoded already, the quotes are part of the text.
+		{Name: "quoted", Type: RecordTypeTXT, Value: `"v=spf1 +all"`},
+		{Name: "old._domainkey", Type: RecordTypeTXT, Value: "v=DKIM1; p="},
+		{Name: "shop", Type: RecordTypeMX, Value: "10 mail.hetzner.com."},
+		{Name: "news", Type: RecordTypeTXT, Value: "v=spf1 include:_spf.hetzner.com"},
+		{Name: "news", Type: RecordTypeTXT, Value: "v=spf1 ptr -all"},
+		{Name: "_mta-sts", Type: RecordTypeTXT, Value: "v=STSv1; id=1"},
+		{Name: "www", Type: RecordTypeA, Value: "192.0.2.1"},
+	}
+
+	issues := LintMailAuth(zone, records)
+	expected := []string{
+		"@: error: spf: +all allows any sender",
+		"@: warning: receives mail but has no DMARC record",
+		"_mta-sts: warning: mta-sts: no TLS-RPT record to report failures",
+		"mail._domainkey: warning: dkim: 1024 bit rsa key is weak, use at least 2048 bits",
+		"news: error: 2 SPF records, receivers treat this as no record",
+		"old._domainkey: warning: dkim: key revoked",
+		"shop: warning: receives mail but has no SPF record",
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %d issues but got %v", len(expected), issues)
+	}
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Errorf("expected issue %q but got %q", expected[i], issue)
+		}
+	}
+
+	records = []*Record{
+		{Name: "@", Type: RecordTypeMX, Value: "10 mail.hetzner.com."},
+		{Name: "@", Type: RecordTypeTXT, Value: "v=spf1 mx -all"},
+		{Name: "_dmarc", Type: RecordTypeTXT, Value: "v=DMARC1; p=none"},
+	}
+	issues = LintMailAuth(zone, records)
+	if len(issues) != 2 {
+		t.Errorf("unexpected issues %v", issues)
+	}
+}
+
+func TestRecordClientLintMailAuth(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	api.AddRecord(zoneID, "@", RecordTypeMX, "10 mail.hetzner.com.", 0)
+	api.AddRecord(zoneID, "@", RecordTypeTXT, "v=spf1 mx -all", 0)
+	api.AddRecord(zoneID, "_dmarc", RecordTypeTXT, "v=DMARC1; p=reject; rua=mailto:dmarc@hetzner.com", 0)
+
+	issues, _, err := env.Client.Record.LintMailAuth(env.Context, &Zone{ID: zoneID, Name: "hetzner.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("unexpected issues %v", issues)
+	}
+}
@@ -0,0 +1,253 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	mdns "github.com/miekg/dns"
+)
+
+// Migration holds the records of a zone exported from another provider,
+// normalized for the Hetzner DNS API.
+type Migration struct {
+	// Zone is the name of the migrated zone.
+	Zone string
+	// Records holds the records to create, with names relative to the zone.
+	// Records without a ttl use the default ttl of the zone.
+	Records []*RecordEntry
+	// Skipped holds the records which can not be migrated.
+	Skipped []*SkippedRecord
+}
+
+// SkippedRecord is a record of an export which can not be migrated.
+type SkippedRecord struct {
+	Name   string
+	Type   string
+	Value  string
+	Reason string
+}
+
+// add normalizes the record and adds it to the migration. SOA records are
+// dropped as the API manages them, records which can not be migrated are
+// added to the skipped records.
+func (m *Migration) add(name, typ string, ttl *int, value string) {
+	typ = strings.ToUpper(typ)
+	skip := func(reason string) {
+		m.Skipped = append(m.Skipped, &SkippedRecord{Name: name, Type: typ, Value: value, Reason: reason})
+	}
+
+	recordType := RecordType(typ)
+	switch {
+	case recordType == RecordTypeSOA:
+		return
+	case !recordType.valid():
+		skip("unsupported record type")
+		return
+	case recordType == RecordTypeNS && IsApex(m.Zone, name):
+		skip("name servers of the zone are managed by Hetzner")
+		return
+	}
+
+	rrTtl := 0
+	if ttl != nil {
+		rrTtl = *ttl
+	}
+
+	rr, err := parseRR(m.Zone, mdns.Fqdn(name), rrTtl, recordType, value)
+	if err != nil {
+		skip(err.Error())
+		return
+	}
+
+	entry := entryFromRR(m.Zone, rr)
+	entry.Type = recordType
+	entry.Ttl = nil
+	if ttl != nil {
+		entry.Ttl = &rrTtl
+	}
+	m.Records = append(m.Records, entry)
+}
+
+// RecordCreateOpts returns the options to create the records of the
+// migration in the zone.
+func (m *Migration) RecordCreateOpts(zone *Zone) []RecordCreateOpts {
+	opts := make([]RecordCreateOpts, 0, len(m.Records))
+	for _, rec := range m.Records {
+		opts = append(opts, RecordCreateOpts{
+			Name:  rec.Name,
+			Ttl:   rec.Ttl,
+			Type:  rec.Type,
+			Value: rec.Value,
+			Zone:  zone,
+		})
+	}
+
+	return opts
+}
+
+// WriteZoneFile writes the records of the migration as a zone file which
+// can be imported with ZoneClient.Import.
+func (m *Migration) WriteZoneFile(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "$ORIGIN %s\n", mdns.Fqdn(m.Zone)); err != nil {
+		return err
+	}
+
+	for _, rec := range m.Records {
+		line := rec.Name
+		if rec.Ttl != nil {
+			line += " " + strconv.Itoa(*rec.Ttl)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s IN %s %s\n", line, rec.Type, zoneFileValue(rec.Type, rec.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportMigration creates the records of the migration in the zone.
+func (c RecordClient) ImportMigration(ctx context.Context, zone *Zone, m *Migration) (*RecordBulkCreateResponse, *Response, error) {
+	return c.BulkCreate(ctx, m.RecordCreateOpts(zone))
+}
+
+// route53RecordSets is the output of the Route53 list-resource-record-sets
+// command.
+type route53RecordSets struct {
+	ResourceRecordSets []struct {
+		Name            string
+		Type            string
+		TTL             *int
+		SetIdentifier   string
+		ResourceRecords []struct {
+			Value string
+		}
+		AliasTarget *struct {
+			DNSName string
+		}
+	}
+}
+
+var route53Escape = regexp.MustCompile(`\\[0-7]{3}`)
+
+// ParseRoute53 parses the JSON output of the Route53 list-resource-record-sets
+// command for the zone. Alias records and records with a routing policy are
+// skipped.
+func ParseRoute53(r io.Reader, zone string) (*Migration, error) {
+	var sets route53RecordSets
+	if err := json.NewDecoder(r).Decode(&sets); err != nil {
+		return nil, err
+	}
+
+	m := &Migration{Zone: strings.TrimSuffix(zone, ".")}
+	for _, set := range sets.ResourceRecordSets {
+		// Route53 escapes special characters like the wildcard as octal codes.
+		name := route53Escape.ReplaceAllStringFunc(set.Name, func(code string) string {
+			c, _ := strconv.ParseUint(code[1:], 8, 8)
+			return string(rune(c))
+		})
+
+		switch {
+		case set.AliasTarget != nil:
+			m.Skipped = append(m.Skipped, &SkippedRecord{
+				Name: name, Type: set.Type, Value: set.AliasTarget.DNSName, Reason: "alias records are not supported",
+			})
+			continue
+		case set.SetIdentifier != "":
+			for _, rec := range set.ResourceRecords {
+				m.Skipped = append(m.Skipped, &SkippedRecord{
+					Name: name, Type: set.Type, Value: rec.Value, Reason: "routing policies are not supported",
+				})
+			}
+			continue
+		}
+
+		for _, rec := range set.ResourceRecords {
+			m.add(name, set.Type, set.TTL, rec.Value)
+		}
+	}
+
+	return m, nil
+}
+
+// ParseCloudflare parses a BIND zone file exported by Cloudflare for the
+// zone. Proxied records, marked with a cf-proxied:true tag, are skipped as
+// their addresses point to the origin instead of the Cloudflare proxy. The
+// automatic ttl of Cloudflare is replaced by the default ttl of the zone.
+func ParseCloudflare(r io.Reader, zone string) (*Migration, error) {
+	m := &Migration{Zone: strings.TrimSuffix(zone, ".")}
+
+	zp := mdns.NewZoneParser(r, mdns.Fqdn(zone), "")
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		hdr := rr.Header()
+		typ := mdns.TypeToString[hdr.Rrtype]
+		value := rrValue(rr)
+
+		if strings.Contains(zp.Comment(), "cf-proxied:true") {
+			m.Skipped = append(m.Skipped, &SkippedRecord{
+				Name: hdr.Name, Type: typ, Value: value, Reason: "proxied records are not supported",
+			})
+			continue
+		}
+
+		var ttl *int
+		if hdr.Ttl > 1 {
+			t := int(hdr.Ttl)
+			ttl = &t
+		}
+		m.add(hdr.Name, typ, ttl, value)
+	}
+	if err := zp.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// powerDNSZone is a zone returned by the PowerDNS API.
+type powerDNSZone struct {
+	Name   string `json:"name"`
+	RRSets []struct {
+		Name    string `json:"name"`
+		Type    string `json:"type"`
+		Ttl     *int   `json:"ttl"`
+		Records []struct {
+			Content  string `json:"content"`
+			Disabled bool   `json:"disabled"`
+		} `json:"records"`
+	} `json:"rrsets"`
+}
+
+// ParsePowerDNS parses a zone returned by the PowerDNS API. Disabled records
+// and PowerDNS specific types like ALIAS and LUA are skipped.
+func ParsePowerDNS(r io.Reader) (*Migration, error) {
+	var zone powerDNSZone
+	if err := json.NewDecoder(r).Decode(&zone); err != nil {
+		return nil, err
+	}
+	if zone.Name == "" {
+		return nil, errors.New("zone name required")
+	}
+
+	m := &Migration{Zone: strings.TrimSuffix(zone.Name, ".")}
+	for _, set := range zone.RRSets {
+		for _, rec := range set.Records {
+			if rec.Disabled {
+				m.Skipped = append(m.Skipped, &SkippedRecord{
+					Name: set.Name, Type: set.Type, Value: rec.Content, Reason: "record is disabled",
+				})
+				continue
+			}
+
+			m.add(set.Name, set.Type, set.Ttl, rec.Content)
+		}
+	}
+
+	return m, nil
+}
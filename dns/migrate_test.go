@@ -0,0 +1,113 @@
+package dns
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseRoute53(t *testing.T) {
+	as := newAssert(t)
+
+	export := `{"ResourceRecordSets": [
+		{"Name": "hetzner.com.", "Type": "SOA", "TTL": 900, "ResourceRecords": [{"Value": "ns-1.awsdns-1.org. awsdns-hostmaster.amazon.com. 1 7200 900 1209600 86400"}]},
+		{"Name": "hetzner.com.", "Type": "NS", "TTL": 172800, "ResourceRecords": [{"Value": "ns-1.awsdns-1.org."}]},
+		{"Name": "hetzner.com.", "Type": "MX", "TTL": 300, "ResourceRecords": [{"Value": "10 mail.hetzner.com."}, {"Value": "20 backup.hetzner.com."}]},
+		{"Name": "\\052.hetzner.com.", "Type": "A", "TTL": 60, "ResourceRecords": [{"Value": "192.0.2.1"}]},
+		{"Name": "hetzner.com.", "Type": "TXT", "TTL": 300, "ResourceRecords": [{"Value": "\"v=spf1 -all\""}]},
+		{"Name": "www.hetzner.com.", "Type": "A", "AliasTarget": {"DNSName": "d1.cloudfront.net.", "HostedZoneId": "Z2"}},
+		{"Name": "geo.hetzner.com.", "Type": "A", "TTL": 60, "SetIdentifier": "eu", "ResourceRecords": [{"Value": "192.0.2.2"}]}
+	]}`
+
+	m, err := ParseRoute53(strings.NewReader(export), "hetzner.com.")
+	if !as.NoError(err) || !as.EqInt(4, len(m.Records)) || !as.EqInt(3, len(m.Skipped)) {
+		return
+	}
+
+	as.EqStr("hetzner.com", m.Zone)
+	as.EqStr("@", m.Records[0].Name)
+	as.EqStr("20 backup.hetzner.com.", m.Records[1].Value)
+	as.EqStr("*", m.Records[2].Name)
+	as.EqInt(60, *m.Records[2].Ttl)
+	as.EqStr("v=spf1 -all", m.Records[3].Value)
+	as.EqStr("d1.cloudfront.net.", m.Skipped[1].Value)
+	as.EqStr("routing policies are not supported", m.Skipped[2].Reason)
+}
+
+func TestParseCloudflare(t *testing.T) {
+	as := newAssert(t)
+
+	export := `;; Domain:     hetzner.com.
+hetzner.com.	3600	IN	SOA	ada.ns.cloudflare.com. dns.cloudflare.com. 2 10000 2400 604800 3600
+hetzner.com.	86400	IN	NS	ada.ns.cloudflare.com.
+hetzner.com.	1	IN	A	192.0.2.1 ; cf_tags=cf-proxied:true
+api.hetzner.com.	1	IN	A	192.0.2.2 ; cf_tags=cf-proxied:false
+hetzner.com.	300	IN	CAA	0 issue "letsencrypt.org"
+hetzner.com.	300	IN	HTTPS	1 . alpn="h2"
+`
+
+	m, err := ParseCloudflare(strings.NewReader(export), "hetzner.com")
+	if !as.NoError(err) || !as.EqInt(2, len(m.Records)) || !as.EqInt(3, len(m.Skipped)) {
+		return
+	}
+
+	as.EqStr("api", m.Records[0].Name)
+	if m.Records[0].Ttl != nil {
+		t.Error("expected zone default ttl for automatic ttl")
+	}
+	as.EqStr(`0 issue "letsencrypt.org"`, m.Records[1].Value)
+	as.EqStr("proxied records are not supported", m.Skipped[1].Reason)
+	as.EqStr("unsupported record type", m.Skipped[2].Reason)
+}
+
+func TestParsePowerDNS(t *testing.T) {
+	as := newAssert(t)
+
+	export := `{"name": "hetzner.com.", "rrsets": [
+		{"name": "hetzner.com.", "type": "ALIAS", "ttl": 300, "records": [{"content": "lb.hetzner.com.", "disabled": false}]},
+		{"name": "www.hetzner.com.", "type": "CNAME", "ttl": 300, "records": [{"content": "hetzner.com.", "disabled": false}]},
+		{"name": "old.hetzner.com.", "type": "A", "ttl": 300, "records": [{"content": "192.0.2.1", "disabled": true}]},
+		{"name": "_sip._tcp.hetzner.com.", "type": "SRV", "ttl": 300, "records": [{"content": "10 60 5060 sip.hetzner.com.", "disabled": false}]},
+		{"name": "bad.hetzner.com.", "type": "A", "ttl": 300, "records": [{"content": "not-an-ip", "disabled": false}]}
+	]}`
+
+	m, err := ParsePowerDNS(strings.NewReader(export))
+	if !as.NoError(err) || !as.EqInt(2, len(m.Records)) || !as.EqInt(3, len(m.Skipped)) {
+		return
+	}
+
+	as.EqStr("www", m.Records[0].Name)
+	as.EqStr("_sip._tcp", m.Records[1].Name)
+	as.EqStr("ALIAS", m.Skipped[0].Type)
+	as.EqStr("record is disabled", m.Skipped[1].Reason)
+	as.EqStr("bad.hetzner.com.", m.Skipped[2].Name)
+
+	_, err = ParsePowerDNS(strings.NewReader(`{"rrsets": []}`))
+	as.Error(err)
+}
+
+func TestMigrationImport(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+
+	ttl := 300
+	m := &Migration{Zone: "hetzner.com", Records: []*RecordEntry{
+		{Type: RecordTypeA, Name: "www", Value: "192.0.2.1", Ttl: &ttl},
+		{Type: RecordTypeTXT, Name: "@", Value: "v=spf1 -all"},
+	}}
+
+	_, _, err := env.Client.Record.ImportMigration(env.Context, &Zone{ID: zoneID}, m)
+	if as.NoError(err) && as.EqInt(2, len(api.ZoneRecords(zoneID))) {
+		as.EqInt(300, api.ZoneRecords(zoneID)[0].Ttl)
+	}
+
+	buf := &bytes.Buffer{}
+	if as.NoError(m.WriteZoneFile(buf)) {
+		as.EqStr("$ORIGIN hetzner.com.\nwww 300 IN A 192.0.2.1\n@ IN TXT \"v=spf1 -all\"\n", buf.String())
+	}
+}
@@ -0,0 +1,180 @@
+package dns
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// idnaProfile converts labels according to IDNA 2008 with the UTS 46 mapping
+// used for lookups, e.g. lower casing Unicode letters.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+)
+
+// ToFQDN returns the fully qualified name, with trailing dot, of a name in
+// the zone. The name may be relative to the zone, "@" or empty for the apex,
+// or already contain the zone name with or without trailing dot.
+func ToFQDN(zone, name string) string {
+	zone = strings.TrimSuffix(zone, ".")
+
+	switch {
+	case name == "" || name == "@":
+		return zone + "."
+	case strings.HasSuffix(name, "."):
+		return name
+	case zone == "":
+		return name + "."
+	case strings.EqualFold(name, zone) || inZone(zone, name):
+		return name + "."
+	default:
+		return name + "." + zone + "."
+	}
+}
+
+// ToRelative returns the name relative to the zone, "@" for the apex. The
+// name may be fully qualified, contain the zone name without trailing dot or
+// already be relative. Names outside of the zone are returned fully
+// qualified.
+func ToRelative(zone, name string) string {
+	zone = strings.TrimSuffix(zone, ".")
+
+	fqdn := ToFQDN(zone, name)
+	trimmed := strings.TrimSuffix(fqdn, ".")
+	switch {
+	case strings.EqualFold(trimmed, zone):
+		return "@"
+	case inZone(zone, trimmed):
+		return trimmed[:len(trimmed)-len(zone)-1]
+	default:
+		return fqdn
+	}
+}
+
+// inZone reports whether name, without trailing dot, is a subdomain of zone.
+func inZone(zone, name string) bool {
+	return zone != "" && len(name) > len(zone) && name[len(name)-len(zone)-1] == '.' &&
+		strings.EqualFold(name[len(name)-len(zone):], zone)
+}
+
+// IsApex reports whether the name is the apex of the zone.
+func IsApex(zone, name string) bool {
+	return ToRelative(zone, name) == "@"
+}
+
+// IsWildcard reports whether the name is a wildcard name, e.g. "*" or
+// "*.www".
+func IsWildcard(name string) bool {
+	return name == "*" || strings.HasPrefix(name, "*.")
+}
+
+// FoldName folds the case of a name. Names are compared case-insensitively
+// in the DNS for ASCII letters only, other characters are kept.
+func FoldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, name)
+}
+
+// CanonicalName returns the fully qualified, case folded ASCII form of a name
+// in the zone, to compare names regardless of how they are written. Names
+// which fail the IDNA conversion are folded as they are.
+func CanonicalName(zone, name string) string {
+	fqdn := ToFQDN(zone, name)
+	if ascii, err := ToASCII(fqdn); err == nil {
+		fqdn = ascii
+	}
+
+	return FoldName(fqdn)
+}
+
+// ToASCII converts the Unicode labels of a name to their punycode A-label
+// form, e.g. "münchen.de" to "xn--mnchen-3ya.de". ASCII labels, including
+// labels like "_dmarc", "*" and "@", are kept as they are.
+func ToASCII(name string) (string, error) {
+	return mapLabels(name, func(label string) (string, error) {
+		if !isASCII(label) {
+			return idnaProfile.ToASCII(label)
+		}
+
+		// A-labels have to decode to a valid Unicode label.
+		if strings.HasPrefix(FoldName(label), "xn--") {
+			if _, err := idnaProfile.ToUnicode(label); err != nil {
+				return "", err
+			}
+		}
+		return label, nil
+	})
+}
+
+// ToUnicode converts the punycode A-labels of a name to their Unicode form,
+// e.g. "xn--mnchen-3ya.de" to "münchen.de".
+func ToUnicode(name string) (string, error) {
+	return mapLabels(name, func(label string) (string, error) {
+		if !strings.HasPrefix(FoldName(label), "xn--") {
+			return label, nil
+		}
+		return idnaProfile.ToUnicode(label)
+	})
+}
+
+// mapLabels applies fn to each label of a name.
+func mapLabels(name string, fn func(label string) (string, error)) (string, error) {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if label == "" {
+			continue
+		}
+
+		mapped, err := fn(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = mapped
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// ListByName returns the records of the zone with the given name, in any of
+// the forms accepted by ToRelative, and type. An empty type returns the
+// records of all types.
+func (c RecordClient) ListByName(ctx context.Context, zone *Zone, name string, typ RecordType) ([]*Record, *Response, error) {
+	return listByName(ctx, c, zone, name, typ)
+}
+
+func listByName(ctx context.Context, api RecordAPI, zone *Zone, name string, typ RecordType) ([]*Record, *Response, error) {
+	records, resp, err := listAllRecords(ctx, api, RecordListOpts{ZoneID: zone.ID})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	canonical := CanonicalName(zone.Name, name)
+	var matches []*Record
+	for _, rec := range records {
+		if rec.Zone != nil && rec.Zone.ID != "" && rec.Zone.ID != zone.ID {
+			continue
+		}
+		if (typ == "" || rec.Type == typ) && CanonicalName(zone.Name, rec.Name) == canonical {
+			matches = append(matches, rec)
+		}
+	}
+
+	return matches, resp, nil
+}
@@ -0,0 +1,78 @@
+package dns
+
+import (
+	"testing"
+)
+
+func TestNameConversion(t *testing.T) {
+	as := newAssert(t)
+
+	for _, c := range []struct{ name, fqdn, relative string }{
+		{"@", "hetzner.com.", "@"},
+		{"", "hetzner.com.", "@"},
+		{"hetzner.com.", "hetzner.com.", "@"},
+		{"HETZNER.com", "HETZNER.com.", "@"},
+		{"www", "www.hetzner.com.", "www"},
+		{"www.hetzner.com", "www.hetzner.com.", "www"},
+		{"WWW.Hetzner.COM.", "WWW.Hetzner.COM.", "WWW"},
+		{"*.dev", "*.dev.hetzner.com.", "*.dev"},
+		{"other.com.", "other.com.", "other.com."},
+		{"nothetzner.com.", "nothetzner.com.", "nothetzner.com."},
+	} {
+		as.EqStr(c.fqdn, ToFQDN("hetzner.com.", c.name))
+		as.EqStr(c.relative, ToRelative("hetzner.com", c.name))
+	}
+
+	if !IsApex("hetzner.com", "Hetzner.Com.") || IsApex("hetzner.com", "www") {
+		t.Error("unexpected apex detection")
+	}
+	if !IsWildcard("*") || !IsWildcard("*.dev") || IsWildcard("dev.*") {
+		t.Error("unexpected wildcard detection")
+	}
+
+	as.EqStr("www.hetzner.com.", CanonicalName("Hetzner.com", "WWW"))
+	as.EqStr("münchen", FoldName("MüNCHEN"))
+}
+
+func TestNameIDNA(t *testing.T) {
+	as := newAssert(t)
+
+	ascii, err := ToASCII("_dmarc.Bücher.münchen.de.")
+	if as.NoError(err) {
+		as.EqStr("_dmarc.xn--bcher-kva.xn--mnchen-3ya.de.", ascii)
+	}
+
+	unicode, err := ToUnicode("_dmarc.xn--bcher-kva.xn--mnchen-3ya.de.")
+	if as.NoError(err) {
+		as.EqStr("_dmarc.bücher.münchen.de.", unicode)
+	}
+
+	as.EqStr(CanonicalName("xn--mnchen-3ya.de", "www"), CanonicalName("münchen.de", "WWW"))
+
+	_, err = ToASCII("a‍b.de")
+	as.Error(err)
+}
+
+func TestRecordClientListByName(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 0)
+	api.AddRecord(zoneID, "WWW", RecordTypeAAAA, "2001:db8::1", 0)
+	api.AddRecord(zoneID, "@", RecordTypeA, "192.0.2.2", 0)
+
+	zone := &Zone{ID: zoneID, Name: "hetzner.com"}
+	records, _, err := env.Client.Record.ListByName(env.Context, zone, "www.hetzner.com.", "")
+	if as.NoError(err) {
+		as.EqInt(2, len(records))
+	}
+
+	records, _, err = env.Client.Record.ListByName(env.Context, zone, "hetzner.com", RecordTypeA)
+	if as.NoError(err) && as.EqInt(1, len(records)) {
+		as.EqStr("192.0.2.2", records[0].Value)
+	}
+}
@@ -0,0 +1,276 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// octoRecord is a record in an octoDNS zone file.
+type octoRecord struct {
+	Ttl     *int                   `yaml:"ttl,omitempty"`
+	Type    string                 `yaml:"type"`
+	Value   interface{}            `yaml:"value,omitempty"`
+	Values  []interface{}          `yaml:"values,omitempty"`
+	Octodns map[string]interface{} `yaml:"octodns,omitempty"`
+}
+
+// octoFields maps the fields of structured octoDNS values, in the order of
+// the zone file format, per record type.
+var octoFields = map[RecordType][]string{
+	RecordTypeMX:   {"preference", "exchange"},
+	RecordTypeSRV:  {"priority", "weight", "port", "target"},
+	RecordTypeCAA:  {"flags", "tag", "value"},
+	RecordTypeDS:   {"key_tag", "algorithm", "digest_type", "digest"},
+	RecordTypeTLSA: {"certificate_usage", "selector", "matching_type", "certificate_association_data"},
+}
+
+// octoAliases holds legacy field names of octoDNS values.
+var octoAliases = map[RecordType]map[string]string{
+	RecordTypeMX: {"priority": "preference", "value": "exchange"},
+}
+
+// ParseOctoDNS parses an octoDNS zone file in YAML format into record
+// entries of the zone with the given id. The apex, written as an empty name
+// by octoDNS, is converted to "@". Record types not supported by the API
+// result in an error.
+func ParseOctoDNS(r io.Reader, zoneID string) ([]*RecordEntry, error) {
+	var file map[string]yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(file))
+	for name := range file {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var entries []*RecordEntry
+	for _, name := range names {
+		node := file[name]
+
+		var records []octoRecord
+		if node.Kind == yaml.SequenceNode {
+			if err := node.Decode(&records); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		} else {
+			var rec octoRecord
+			if err := node.Decode(&rec); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			records = append(records, rec)
+		}
+
+		recName := name
+		if recName == "" {
+			recName = "@"
+		}
+
+		for _, rec := range records {
+			typ := RecordType(strings.ToUpper(rec.Type))
+			if !typ.valid() {
+				return nil, fmt.Errorf("%s: unsupported record type %s", name, rec.Type)
+			}
+
+			values := rec.Values
+			if rec.Value != nil {
+				values = append([]interface{}{rec.Value}, values...)
+			}
+			if len(values) == 0 {
+				return nil, fmt.Errorf("%s %s: value required", name, typ)
+			}
+
+			for _, v := range values {
+				value, err := octoValue(typ, v)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", name, typ, err)
+				}
+
+				var ttl *int
+				if rec.Ttl != nil {
+					t := *rec.Ttl
+					ttl = &t
+				}
+
+				entries = append(entries, &RecordEntry{
+					Type:   typ,
+					ZoneID: zoneID,
+					Name:   recName,
+					Value:  value,
+					Ttl:    ttl,
+				})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// octoValue converts an octoDNS value to a value in zone file format.
+func octoValue(typ RecordType, v interface{}) (string, error) {
+	fields, ok := v.(map[string]interface{})
+	if !ok {
+		s := fmt.Sprint(v)
+		if typ == RecordTypeTXT {
+			s = strings.ReplaceAll(s, `\;`, ";")
+		}
+		if s == "" {
+			return "", errors.New("value required")
+		}
+		return s, nil
+	}
+
+	keys, ok := octoFields[typ]
+	if !ok {
+		return "", fmt.Errorf("structured value not supported for %s records", typ)
+	}
+
+	for alias, key := range octoAliases[typ] {
+		if v, ok := fields[alias]; ok {
+			if _, exists := fields[key]; !exists {
+				fields[key] = v
+			}
+			delete(fields, alias)
+		}
+	}
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		v, ok := fields[key]
+		if !ok {
+			if typ == RecordTypeCAA && key == "flags" {
+				v = 0
+			} else {
+				return "", fmt.Errorf("%s required", key)
+			}
+		}
+
+		s := fmt.Sprint(v)
+		if typ == RecordTypeCAA && key == "value" {
+			s = quoteTXT(s)
+		}
+		parts = append(parts, s)
+		delete(fields, key)
+	}
+
+	for key := range fields {
+		return "", fmt.Errorf("unknown field %s", key)
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// toOctoValue converts a value in zone file format to an octoDNS value.
+func toOctoValue(typ RecordType, value string) interface{} {
+	keys, ok := octoFields[typ]
+	if !ok {
+		if typ == RecordTypeTXT {
+			value = strings.ReplaceAll(value, ";", `\;`)
+		}
+		return value
+	}
+
+	parts := strings.Fields(value)
+	if len(parts) < len(keys) {
+		return value
+	}
+
+	// The last field may contain spaces, e.g. quoted CAA values.
+	last := strings.TrimSpace(value)
+	for _, part := range parts[:len(keys)-1] {
+		last = strings.TrimSpace(last[len(part):])
+	}
+	parts = append(parts[:len(keys)-1], last)
+
+	fields := map[string]interface{}{}
+	for i, key := range keys {
+		if n, err := strconv.Atoi(parts[i]); err == nil && i < len(keys)-1 {
+			fields[key] = n
+			continue
+		}
+		if unquoted, rest, ok := unquoteTXTString(parts[i]); ok && rest == "" {
+			parts[i] = unquoted
+		}
+		fields[key] = parts[i]
+	}
+
+	return fields
+}
+
+// WriteOctoDNS writes the records in octoDNS zone file format. SOA records
+// are left out as they are not managed by octoDNS. octoDNS has a single ttl
+// per record set, records of a set with differing ttls result in an error.
+func WriteOctoDNS(w io.Writer, records []*Record) error {
+	type set struct {
+		rec    *octoRecord
+		ttl    int
+		values []interface{}
+	}
+
+	sets := map[string]*set{}
+	names := map[string][]string{}
+	for _, r := range records {
+		if r.Type == RecordTypeSOA {
+			continue
+		}
+
+		name := r.Name
+		if name == "@" {
+			name = ""
+		}
+
+		key := name + " " + string(r.Type)
+		s, ok := sets[key]
+		if !ok {
+			s = &set{rec: &octoRecord{Type: string(r.Type)}, ttl: r.Ttl}
+			if r.Ttl > 0 {
+				ttl := r.Ttl
+				s.rec.Ttl = &ttl
+			}
+			sets[key] = s
+			names[name] = append(names[name], key)
+		} else if r.Ttl != s.ttl {
+			return fmt.Errorf("%s %s: records have inconsistent ttls %d, %d", r.Name, r.Type, s.ttl, r.Ttl)
+		}
+		s.values = append(s.values, toOctoValue(r.Type, r.Value))
+	}
+
+	file := map[string]interface{}{}
+	for name, setKeys := range names {
+		var recs []*octoRecord
+		for _, key := range setKeys {
+			s := sets[key]
+			if len(s.values) == 1 {
+				s.rec.Value = s.values[0]
+			} else {
+				s.rec.Values = s.values
+			}
+			recs = append(recs, s.rec)
+		}
+
+		sort.Slice(recs, func(i, j int) bool { return recs[i].Type < recs[j].Type })
+		if len(recs) == 1 {
+			file[name] = recs[0]
+		} else {
+			file[name] = recs
+		}
+	}
+
+	if _, err := io.WriteString(w, "---\n"); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(file); err != nil {
+		return err
+	}
+	return enc.Close()
+}
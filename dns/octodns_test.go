@@ -0,0 +1,134 @@
+package dns
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testOctoDNSYAML = `---
+'':
+  - type: A
+    values:
+      - 192.0.2.1
+      - 192.0.2.2
+  - ttl: 600
+    type: MX
+    values:
+      - exchange: mail.hetzner.com.
+        preference: 10
+      - priority: 20
+        value: backup.hetzner.com.
+  - type: CAA
+    value:
+      tag: issue
+      value: letsencrypt.org
+  - type: TXT
+    value: v=spf1 include:_spf.hetzner.com -all\; comment
+_sip._tcp:
+  type: SRV
+  value:
+    port: 5060
+    priority: 10
+    target: sip.hetzner.com.
+    weight: 60
+www:
+  octodns:
+    cloudflare:
+      proxied: false
+  type: CNAME
+  value: hetzner.com.
+`
+
+func TestParseOctoDNS(t *testing.T) {
+	as := newAssert(t)
+
+	entries, err := ParseOctoDNS(strings.NewReader(testOctoDNSYAML), "1")
+	if !as.NoError(err) || !as.EqInt(8, len(entries)) {
+		return
+	}
+
+	as.EqStr("@", entries[0].Name)
+	as.EqStr("192.0.2.2", entries[1].Value)
+	as.EqStr("10 mail.hetzner.com.", entries[2].Value)
+	as.EqStr("20 backup.hetzner.com.", entries[3].Value)
+	as.EqInt(600, *entries[3].Ttl)
+	as.EqStr(`0 issue "letsencrypt.org"`, entries[4].Value)
+	as.EqStr("v=spf1 include:_spf.hetzner.com -all; comment", entries[5].Value)
+	as.EqStr("10 60 5060 sip.hetzner.com.", entries[6].Value)
+	as.EqStr("www", entries[7].Name)
+	as.EqStr("1", entries[7].ZoneID)
+	if entries[0].Ttl != nil {
+		t.Error("expected zone default ttl")
+	}
+
+	invalid := map[string]string{
+		"unsupported type": "www: {type: ALIAS, value: hetzner.com.}",
+		"missing value":    "www: {type: A}",
+		"incomplete mx":    "'': {type: MX, value: {exchange: mail.}}",
+		"unknown field":    "'': {type: MX, value: {exchange: mail., preference: 10, weight: 1}}",
+		"structured a":     "www: {type: A, value: {address: 192.0.2.1}}",
+	}
+	for name, zone := range invalid {
+		if _, err := ParseOctoDNS(strings.NewReader(zone), "1"); err == nil {
+			t.Errorf("%s: missing expected error", name)
+		}
+	}
+}
+
+func TestWriteOctoDNS(t *testing.T) {
+	as := newAssert(t)
+
+	entries, err := ParseOctoDNS(strings.NewReader(testOctoDNSYAML), "1")
+	if !as.NoError(err) {
+		return
+	}
+
+	records := []*Record{{Name: "@", Type: RecordTypeSOA, Value: "hydrogen.ns.hetzner.com. dns.hetzner.com. 1 86400 10800 3600000 3600"}}
+	for _, e := range entries {
+		rec := &Record{Name: e.Name, Type: e.Type, Value: e.Value}
+		if e.Ttl != nil {
+			rec.Ttl = *e.Ttl
+		}
+		records = append(records, rec)
+	}
+	records = append(records,
+		&Record{Name: "quoted", Type: RecordTypeTXT, Value: `"v=DKIM1; " "p=abc"`},
+		&Record{Name: "ca", Type: RecordTypeCAA, Value: `0 iodef "mailto:ca@hetzner.com?subject=caa\009é"`},
+	)
+
+	buf := &bytes.Buffer{}
+	if !as.NoError(WriteOctoDNS(buf, records)) {
+		return
+	}
+
+	parsed, err := ParseOctoDNS(bytes.NewReader(buf.Bytes()), "1")
+	if !as.NoError(err) || !as.EqInt(len(entries)+2, len(parsed)) {
+		return
+	}
+
+	values := map[string]bool{}
+	for _, e := range parsed {
+		values[e.Name+" "+string(e.Type)+" "+e.Value] = true
+	}
+	for _, e := range entries {
+		if !values[e.Name+" "+string(e.Type)+" "+e.Value] {
+			t.Errorf("missing %s %s %s", e.Name, e.Type, e.Value)
+		}
+	}
+	if !values[`quoted TXT "v=DKIM1; " "p=abc"`] {
+		t.Error("expected unchanged TXT value")
+	}
+	if !values[`ca CAA 0 iodef "mailto:ca@hetzner.com?subject=caa\009é"`] {
+		t.Error("expected unchanged CAA value")
+	}
+	if !strings.Contains(buf.String(), `v=DKIM1\; `) {
+		t.Errorf("expected escaped semicolon in %s", buf.String())
+	}
+
+	mixed := []*Record{
+		{Name: "www", Type: RecordTypeA, Value: "192.0.2.1", Ttl: 300},
+		{Name: "www", Type: RecordTypeA, Value: "192.0.2.2"},
+	}
+	as.Error(WriteOctoDNS(&bytes.Buffer{}, mixed))
+}
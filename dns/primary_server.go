@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 
 	"github.com/jobstoit/hetzner-dns-go/dns/schema"
@@ -84,11 +85,8 @@ type PrimaryServerCreateOpts struct {
 }
 
 func (o PrimaryServerCreateOpts) validate() error {
-	if o.Address == "" {
-		return errors.New("address required")
-	}
-	if o.Port < 1 || o.Port > 65535 {
-		return errors.New("invalid port")
+	if err := validatePrimaryServerAddress(o.Address, o.Port); err != nil {
+		return err
 	}
 	if o.ZoneID == "" {
 		return errors.New("zone_id required")
@@ -96,8 +94,33 @@ func (o PrimaryServerCreateOpts) validate() error {
 	return nil
 }
 
+// validatePrimaryServerAddress checks if address is an IPv4 or IPv6 unicast
+// address and port a valid port number.
+func validatePrimaryServerAddress(address string, port int) error {
+	if address == "" {
+		return errors.New("address required")
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return errors.New("address must be an IPv4 or IPv6 address")
+	}
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() {
+		return errors.New("address must be a unicast address")
+	}
+	if port < 1 || port > 65535 {
+		return errors.New("invalid port")
+	}
+	return nil
+}
+
 // Create creates a new primary server record.
 func (c PrimaryServerClient) Create(ctx context.Context, opts PrimaryServerCreateOpts) (*PrimaryServer, *Response, error) {
+	server, resp, err := c.create(ctx, opts)
+	c.client.audit(AuditEvent{Operation: AuditPrimaryServerCreate, Request: opts, After: server}, &Zone{ID: opts.ZoneID}, resp, err)
+	return server, resp, err
+}
+
+func (c PrimaryServerClient) create(ctx context.Context, opts PrimaryServerCreateOpts) (*PrimaryServer, *Response, error) {
 	if err := opts.validate(); err != nil {
 		return nil, nil, err
 	}
@@ -134,11 +157,8 @@ type PrimaryServerUpdateOpts struct {
 }
 
 func (o PrimaryServerUpdateOpts) validate() error {
-	if o.Address == "" {
-		return errors.New("address required")
-	}
-	if o.Port < 1 || o.Port > 65535 {
-		return errors.New("invalid port")
+	if err := validatePrimaryServerAddress(o.Address, o.Port); err != nil {
+		return err
 	}
 	if o.ZoneID == "" {
 		return errors.New("zone_id required")
@@ -148,6 +168,13 @@ func (o PrimaryServerUpdateOpts) validate() error {
 
 // Update updates a primary server record.
 func (c PrimaryServerClient) Update(ctx context.Context, server *PrimaryServer, opts PrimaryServerUpdateOpts) (*PrimaryServer, *Response, error) {
+	before := c.auditedPrimaryServer(ctx, server.ID)
+	updated, resp, err := c.update(ctx, server, opts)
+	c.client.audit(AuditEvent{Operation: AuditPrimaryServerUpdate, Request: opts, Before: before, After: updated}, &Zone{ID: opts.ZoneID}, resp, err)
+	return updated, resp, err
+}
+
+func (c PrimaryServerClient) update(ctx context.Context, server *PrimaryServer, opts PrimaryServerUpdateOpts) (*PrimaryServer, *Response, error) {
 	if err := opts.validate(); err != nil {
 		return nil, nil, err
 	}
@@ -178,6 +205,33 @@ func (c PrimaryServerClient) Update(ctx context.Context, server *PrimaryServer,
 
 // Delete deletes a primary server record.
 func (c PrimaryServerClient) Delete(ctx context.Context, server *PrimaryServer) (*Response, error) {
+	before := c.auditedPrimaryServer(ctx, server.ID)
+	resp, err := c.delete(ctx, server)
+	zone := server.Zone
+	if zone == nil && before != nil {
+		zone = before.Zone
+	}
+	c.client.audit(AuditEvent{Operation: AuditPrimaryServerDelete, Before: before}, zone, resp, err)
+	return resp, err
+}
+
+// auditedPrimaryServer returns the primary server as stored in the API for
+// an audit event, or nil when the client has no audit sinks or the primary
+// server can't be read.
+func (c PrimaryServerClient) auditedPrimaryServer(ctx context.Context, id string) *PrimaryServer {
+	if !c.client.auditing() || id == "" || isDryRunID(id) {
+		return nil
+	}
+
+	server, _, err := c.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+
+	return server
+}
+
+func (c PrimaryServerClient) delete(ctx context.Context, server *PrimaryServer) (*Response, error) {
 	req, err := c.client.NewRequest(ctx, "DELETE", fmt.Sprintf("%s/%s", pathPrimaryServers, server.ID), nil)
 	if err != nil {
 		return nil, err
@@ -111,6 +111,18 @@ func TestPrimaryServerCreate(t *testing.T) {
 	}
 
 	opts.Address = "dns.hetzner.com"
+	_, _, err = env.Client.PrimaryServer.Create(env.Context, opts)
+	if as.Error(err) {
+		as.EqStr("address must be an IPv4 or IPv6 address", err.Error())
+	}
+
+	opts.Address = "127.0.0.1"
+	_, _, err = env.Client.PrimaryServer.Create(env.Context, opts)
+	if as.Error(err) {
+		as.EqStr("address must be a unicast address", err.Error())
+	}
+
+	opts.Address = "2001:db8::53"
 	svr, _, err := env.Client.PrimaryServer.Create(env.Context, opts)
 	if as.NoError(err) {
 		as.EqStr("1", svr.ID)
@@ -146,7 +158,7 @@ func TestPrimaryServerUpdate(t *testing.T) {
 
 	ps := &PrimaryServer{ID: "0"}
 	opts := PrimaryServerUpdateOpts{
-		Address: "dns.hetzner.com",
+		Address: "192.0.2.53",
 		Port:    80,
 		ZoneID:  "2",
 	}
@@ -163,7 +175,7 @@ func TestPrimaryServerUpdate(t *testing.T) {
 		as.EqStr("address required", err.Error())
 	}
 
-	opts.Address = "dns.hetzner.com"
+	opts.Address = "192.0.2.53"
 	svr, _, err := env.Client.PrimaryServer.Update(env.Context, ps, opts)
 	if as.NoError(err) {
 		as.EqStr("1", svr.ID)
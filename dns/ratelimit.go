@@ -0,0 +1,60 @@
+package dns
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces requests evenly while allowing bursts, shared by all
+// requests of a client.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	// next is the time at which the next request would be sent when
+	// requests are sent at the sustained rate.
+	next time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		burst:    burst,
+	}
+}
+
+// wait blocks until the request may be sent or ctx is done. A slot is only
+// taken once it is granted, a wait ended by ctx does not use up a slot.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		l.mu.Lock()
+		now := time.Now()
+		if l.next.Before(now) {
+			l.next = now
+		}
+		delay := l.next.Sub(now) - time.Duration(l.burst-1)*l.interval
+		if delay <= 0 {
+			l.next = l.next.Add(l.interval)
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -31,6 +31,23 @@ const (
 	RecordTypeCAA   RecordType = "CAA"
 )
 
+// recordTypes holds all record types supported by the API.
+var recordTypes = []RecordType{
+	RecordTypeA, RecordTypeAAAA, RecordTypePTR, RecordTypeNS, RecordTypeMX,
+	RecordTypeCNAME, RecordTypeRP, RecordTypeTXT, RecordTypeSOA, RecordTypeHINFO,
+	RecordTypeSRV, RecordTypeDANE, RecordTypeTLSA, RecordTypeDS, RecordTypeCAA,
+}
+
+// valid reports whether the record type is supported by the API.
+func (t RecordType) valid() bool {
+	for _, typ := range recordTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
 // Record represents a record in the Hetzner DNS.
 type Record struct {
 	Type     RecordType
@@ -83,6 +100,11 @@ func (c RecordClient) List(ctx context.Context, opts RecordListOpts) ([]*Record,
 	return records, resp, nil
 }
 
+// listAll returns all records with the given parameters, requesting all pages.
+func (c RecordClient) listAll(ctx context.Context, opts RecordListOpts) ([]*Record, *Response, error) {
+	return listAllRecords(ctx, c, opts)
+}
+
 // GetByID returns a record with the given id.
 func (c RecordClient) GetByID(ctx context.Context, id string) (*Record, *Response, error) {
 	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("%s/%s", pathRecords, id), nil)
@@ -121,21 +143,35 @@ func (o RecordCreateOpts) validate() error {
 	if o.Zone == nil {
 		return errors.New("zone required")
 	}
+	if err := validateRecordValue(o.Type, o.Value); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 // Create creates a new record.
 func (c RecordClient) Create(ctx context.Context, opts RecordCreateOpts) (*Record, *Response, error) {
+	rec, resp, err := c.create(ctx, opts)
+	c.client.audit(AuditEvent{Operation: AuditRecordCreate, Request: opts, After: rec}, opts.Zone, resp, err)
+	return rec, resp, err
+}
+
+func (c RecordClient) create(ctx context.Context, opts RecordCreateOpts) (*Record, *Response, error) {
 	if err := opts.validate(); err != nil {
 		return nil, nil, err
 	}
 
+	name, err := asciiName(opts.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var reqBody schema.RecordCreateRequest
-	reqBody.Name = opts.Name
+	reqBody.Name = name
 	reqBody.Ttl = opts.Ttl
 	reqBody.Type = string(opts.Type)
-	reqBody.Value = opts.Value
+	reqBody.Value = zoneFileValue(opts.Type, opts.Value)
 	reqBody.ZoneID = opts.Zone.ID
 
 	reqBodyData, err := json.Marshal(reqBody)
@@ -164,6 +200,9 @@ type RecordUpdateOpts struct {
 	Type  RecordType
 	Value string
 	Zone  *Zone
+	// IfUnmodified refuses the update with ErrConflict when the record was
+	// modified since the given record was read.
+	IfUnmodified bool
 }
 
 func (o RecordUpdateOpts) validate() error {
@@ -179,21 +218,46 @@ func (o RecordUpdateOpts) validate() error {
 	if o.Zone == nil {
 		return errors.New("zone required")
 	}
+	if err := validateRecordValue(o.Type, o.Value); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 // Update updates a record.
 func (c RecordClient) Update(ctx context.Context, rec *Record, opts RecordUpdateOpts) (*Record, *Response, error) {
+	before := c.auditedRecord(ctx, rec.ID)
+	updated, resp, err := c.update(ctx, rec, opts)
+	c.client.audit(AuditEvent{Operation: AuditRecordUpdate, Request: opts, Before: before, After: updated}, opts.Zone, resp, err)
+	return updated, resp, err
+}
+
+func (c RecordClient) update(ctx context.Context, rec *Record, opts RecordUpdateOpts) (*Record, *Response, error) {
 	if err := opts.validate(); err != nil {
 		return nil, nil, err
 	}
 
+	name, err := asciiName(opts.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.IfUnmodified {
+		current, resp, err := c.GetByID(ctx, rec.ID)
+		if err != nil {
+			return nil, resp, err
+		}
+		if err := checkUnmodified(rec.Modified, current.Modified); err != nil {
+			return nil, resp, err
+		}
+	}
+
 	var reqBody schema.RecordUpdateRequest
-	reqBody.Name = opts.Name
+	reqBody.Name = name
 	reqBody.Ttl = opts.Ttl
 	reqBody.Type = string(opts.Type)
-	reqBody.Value = opts.Value
+	reqBody.Value = zoneFileValue(opts.Type, opts.Value)
 	reqBody.ZoneID = opts.Zone.ID
 
 	reqBodyData, err := json.Marshal(reqBody)
@@ -217,6 +281,32 @@ func (c RecordClient) Update(ctx context.Context, rec *Record, opts RecordUpdate
 
 // Delete deletes a record.
 func (c RecordClient) Delete(ctx context.Context, rec *Record) (*Response, error) {
+	before := c.auditedRecord(ctx, rec.ID)
+	resp, err := c.delete(ctx, rec)
+	zone := rec.Zone
+	if zone == nil && before != nil {
+		zone = before.Zone
+	}
+	c.client.audit(AuditEvent{Operation: AuditRecordDelete, Before: before}, zone, resp, err)
+	return resp, err
+}
+
+// auditedRecord returns the record as stored in the API for an audit event,
+// or nil when the client has no audit sinks or the record can't be read.
+func (c RecordClient) auditedRecord(ctx context.Context, id string) *Record {
+	if !c.client.auditing() || id == "" || isDryRunID(id) {
+		return nil
+	}
+
+	rec, _, err := c.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+
+	return rec
+}
+
+func (c RecordClient) delete(ctx context.Context, rec *Record) (*Response, error) {
 	req, err := c.client.NewRequest(ctx, "DELETE", fmt.Sprintf("%s/%s", pathRecords, rec.ID), nil)
 	if err != nil {
 		return nil, err
@@ -243,6 +333,16 @@ type RecordBulkCreateResponse struct {
 
 // BulkCreate creates multiple records.
 func (c RecordClient) BulkCreate(ctx context.Context, bulkOpts []RecordCreateOpts) (*RecordBulkCreateResponse, *Response, error) {
+	created, resp, err := c.bulkCreate(ctx, bulkOpts)
+	event := AuditEvent{Operation: AuditRecordBulkCreate, Request: bulkOpts}
+	if created != nil {
+		event.After = created.Records
+	}
+	c.client.audit(event, bulkZone(bulkOpts), resp, err)
+	return created, resp, err
+}
+
+func (c RecordClient) bulkCreate(ctx context.Context, bulkOpts []RecordCreateOpts) (*RecordBulkCreateResponse, *Response, error) {
 	for _, opt := range bulkOpts {
 		if err := opt.validate(); err != nil {
 			return nil, nil, err
@@ -253,10 +353,14 @@ func (c RecordClient) BulkCreate(ctx context.Context, bulkOpts []RecordCreateOpt
 	reqBody.Records = make([]schema.RecordCreateRequest, 0, len(bulkOpts))
 	for _, opt := range bulkOpts {
 		var r schema.RecordCreateRequest
-		r.Name = opt.Name
+		name, err := asciiName(opt.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		r.Name = name
 		r.Ttl = opt.Ttl
 		r.Type = string(opt.Type)
-		r.Value = opt.Value
+		r.Value = zoneFileValue(opt.Type, opt.Value)
 		r.ZoneID = opt.Zone.ID
 
 		reqBody.Records = append(reqBody.Records, r)
@@ -320,6 +424,9 @@ func (o RecordBulkUpdateOpts) validate() error {
 	if o.Zone == nil {
 		return errors.New("zone required")
 	}
+	if err := validateRecordValue(o.Type, o.Value); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -332,6 +439,60 @@ type RecordBulkUpdateResponse struct {
 
 // BulkUpdate updates multiple records.
 func (c RecordClient) BulkUpdate(ctx context.Context, bulkOpts []RecordBulkUpdateOpts) (*RecordBulkUpdateResponse, *Response, error) {
+	before := c.auditedRecords(ctx, bulkOpts)
+	updated, resp, err := c.bulkUpdate(ctx, bulkOpts)
+	event := AuditEvent{Operation: AuditRecordBulkUpdate, Request: bulkOpts}
+	if len(before) > 0 {
+		event.Before = before
+	}
+	if updated != nil {
+		event.After = updated.Records
+	}
+	c.client.audit(event, bulkUpdateZone(bulkOpts), resp, err)
+	return updated, resp, err
+}
+
+// auditedRecords returns the records to update as stored in the API for an
+// audit event, listing the records of each zone once. Records which can't be
+// read are left out.
+func (c RecordClient) auditedRecords(ctx context.Context, bulkOpts []RecordBulkUpdateOpts) []*Record {
+	if !c.client.auditing() {
+		return nil
+	}
+
+	zones := map[string]map[string]*Record{}
+	var records []*Record
+	for _, opts := range bulkOpts {
+		if opts.ID == "" || isDryRunID(opts.ID) {
+			continue
+		}
+		if opts.Zone == nil || opts.Zone.ID == "" || isDryRunID(opts.Zone.ID) {
+			if rec := c.auditedRecord(ctx, opts.ID); rec != nil {
+				records = append(records, rec)
+			}
+			continue
+		}
+
+		byID, ok := zones[opts.Zone.ID]
+		if !ok {
+			byID = map[string]*Record{}
+			list, _, err := c.listAll(ctx, RecordListOpts{ZoneID: opts.Zone.ID})
+			if err == nil {
+				for _, rec := range list {
+					byID[rec.ID] = rec
+				}
+			}
+			zones[opts.Zone.ID] = byID
+		}
+		if rec, ok := byID[opts.ID]; ok {
+			records = append(records, rec)
+		}
+	}
+
+	return records
+}
+
+func (c RecordClient) bulkUpdate(ctx context.Context, bulkOpts []RecordBulkUpdateOpts) (*RecordBulkUpdateResponse, *Response, error) {
 	for _, opts := range bulkOpts {
 		if err := opts.validate(); err != nil {
 			return nil, nil, err
@@ -343,9 +504,13 @@ func (c RecordClient) BulkUpdate(ctx context.Context, bulkOpts []RecordBulkUpdat
 	for _, opts := range bulkOpts {
 		var recBody schema.RecordBulkUpdateEntry
 		recBody.ID = opts.ID
-		recBody.Name = opts.Name
+		name, err := asciiName(opts.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		recBody.Name = name
 		recBody.Type = string(opts.Type)
-		recBody.Value = opts.Value
+		recBody.Value = zoneFileValue(opts.Type, opts.Value)
 		recBody.Ttl = opts.Ttl
 		recBody.ZoneID = opts.Zone.ID
 
@@ -0,0 +1,73 @@
+package dns
+
+import (
+	"fmt"
+	"strings"
+
+	mdns "github.com/miekg/dns"
+)
+
+// parseRR parses a record with a name relative to origin, as used by the
+// API, into a resource record.
+func parseRR(origin, name string, ttl int, typ RecordType, value string) (mdns.RR, error) {
+	if typ == RecordTypeDANE {
+		typ = RecordTypeTLSA
+	}
+	if name == "" {
+		name = "@"
+	}
+
+	line := fmt.Sprintf("%s %d IN %s %s", name, ttl, typ, zoneFileValue(typ, value))
+	zp := mdns.NewZoneParser(strings.NewReader(line), mdns.Fqdn(origin), "")
+	rr, ok := zp.Next()
+	if err := zp.Err(); err != nil {
+		return nil, err
+	}
+	if !ok || rr == nil {
+		return nil, fmt.Errorf("invalid %s record %s", typ, name)
+	}
+
+	return rr, nil
+}
+
+// zoneFileValue returns the value as it must be written in a zone file and
+// sent to the API. TXT values are encoded with EncodeTXT, values which are
+// already encoded are split anew.
+func zoneFileValue(typ RecordType, value string) string {
+	if typ != RecordTypeTXT {
+		return value
+	}
+
+	return EncodeTXT(DecodeTXT(value))
+}
+
+// entryFromRR converts a resource record to a RecordEntry with a name
+// relative to origin.
+func entryFromRR(origin string, rr mdns.RR) *RecordEntry {
+	hdr := rr.Header()
+	ttl := int(hdr.Ttl)
+
+	return &RecordEntry{
+		Type:  RecordType(mdns.TypeToString[hdr.Rrtype]),
+		Name:  ToRelative(origin, hdr.Name),
+		Value: txtText(mdns.TypeToString[hdr.Rrtype], rrValue(rr)),
+		Ttl:   &ttl,
+	}
+}
+
+// rrValue returns the data of a resource record in zone file format.
+func rrValue(rr mdns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+// rrKey returns a key identifying the name, type and data of a resource
+// record regardless of its ttl and the case of names.
+func rrKey(rr mdns.RR) string {
+	hdr := rr.Header()
+	value := rrValue(rr)
+	if hdr.Rrtype != mdns.TypeTXT {
+		value = strings.ToLower(value)
+	}
+
+	return FoldName(hdr.Name) + " " + mdns.TypeToString[hdr.Rrtype] + " " + value
+}
@@ -0,0 +1,212 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RRSet represents the records of a zone with the same name and type.
+type RRSet struct {
+	Zone *Zone
+	Name string
+	Type RecordType
+	// Ttl is the ttl of the set, 0 for the default ttl of the zone.
+	Ttl    int
+	Values []string
+	// Records holds the records of the set as stored in the API.
+	Records []*Record
+}
+
+// rrSetKey returns the key identifying the set of a record. Records returned
+// by the API only reference their zone by id, the id therefore keeps the sets
+// of different zones apart.
+func rrSetKey(zone *Zone, name string, typ RecordType) string {
+	zoneID, zoneName := "", ""
+	if zone != nil {
+		zoneID, zoneName = zone.ID, zone.Name
+	}
+
+	return zoneID + " " + CanonicalName(zoneName, name) + " " + string(typ)
+}
+
+// GroupRRSets groups the records by zone, name and type in the order the
+// sets first appear. The ttl of a set is taken from its first record.
+func GroupRRSets(records []*Record) []*RRSet {
+	var sets []*RRSet
+	byKey := map[string]*RRSet{}
+	for _, rec := range records {
+		key := rrSetKey(rec.Zone, rec.Name, rec.Type)
+		set, ok := byKey[key]
+		if !ok {
+			set = &RRSet{Zone: rec.Zone, Name: rec.Name, Type: rec.Type, Ttl: rec.Ttl}
+			byKey[key] = set
+			sets = append(sets, set)
+		}
+
+		set.Values = append(set.Values, rec.Value)
+		set.Records = append(set.Records, rec)
+	}
+
+	return sets
+}
+
+// Warnings returns the inconsistencies of the set. Resolvers cache a set
+// with a single ttl, records with differing ttls are therefore reported.
+func (s *RRSet) Warnings() []string {
+	ttls := map[int]bool{}
+	for _, rec := range s.Records {
+		ttls[rec.Ttl] = true
+	}
+	if len(ttls) < 2 {
+		return nil
+	}
+
+	values := make([]int, 0, len(ttls))
+	for ttl := range ttls {
+		values = append(values, ttl)
+	}
+	sort.Ints(values)
+
+	strs := make([]string, len(values))
+	for i, ttl := range values {
+		strs[i] = strconv.Itoa(ttl)
+	}
+
+	return []string{fmt.Sprintf("%s %s: records have inconsistent ttls %s", s.Name, s.Type, strings.Join(strs, ", "))}
+}
+
+// rrSetValueKey returns the key to match values of the set regardless of
+// their formatting.
+func rrSetValueKey(zone *Zone, typ RecordType, value string) string {
+	if zone != nil && zone.Name != "" {
+		if rr, err := parseRR(zone.Name, "@", 0, typ, value); err == nil {
+			return rrKey(rr)
+		}
+	}
+
+	return value
+}
+
+// ReplaceRRSet converges the records of the zone with the name and type of
+// the set to its values and ttl, see ReplaceRRSet.
+func (c RecordClient) ReplaceRRSet(ctx context.Context, set *RRSet) (*RRSet, *Response, error) {
+	return ReplaceRRSet(ctx, c, set)
+}
+
+// ReplaceRRSet converges the records of the zone with the name and type of
+// the set to its values and ttl through api. Matching records are kept,
+// changed records are updated and missing records are created in bulk before
+// the remaining records are deleted, keeping the set resolvable during the
+// change. A set without values deletes all its records.
+func ReplaceRRSet(ctx context.Context, api RecordAPI, set *RRSet) (*RRSet, *Response, error) {
+	if set.Zone == nil || set.Zone.ID == "" {
+		return nil, nil, errors.New("zone required")
+	}
+	if set.Name == "" {
+		return nil, nil, errors.New("name required")
+	}
+	if !set.Type.valid() {
+		return nil, nil, fmt.Errorf("invalid record type %s", set.Type)
+	}
+
+	existing, resp, err := listByName(ctx, api, set.Zone, set.Name, set.Type)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var ttl *int
+	if set.Ttl > 0 {
+		t := set.Ttl
+		ttl = &t
+	}
+
+	// Keep the records with a desired value and pair the remaining records
+	// with the remaining values to update them.
+	result := &RRSet{Zone: set.Zone, Name: set.Name, Type: set.Type, Ttl: set.Ttl}
+	matched := make([]bool, len(existing))
+	var updates []RecordBulkUpdateOpts
+	var missing []string
+	for _, value := range set.Values {
+		valueKey := rrSetValueKey(set.Zone, set.Type, value)
+
+		found := false
+		for i, rec := range existing {
+			if matched[i] || rrSetValueKey(set.Zone, set.Type, rec.Value) != valueKey {
+				continue
+			}
+
+			matched[i] = true
+			found = true
+			if rec.Ttl == set.Ttl {
+				result.Records = append(result.Records, rec)
+			} else {
+				updates = append(updates, RecordBulkUpdateOpts{ID: rec.ID, Type: set.Type, Zone: set.Zone, Name: set.Name, Value: value, Ttl: ttl})
+			}
+			break
+		}
+		if !found {
+			missing = append(missing, value)
+		}
+	}
+
+	var creates []RecordCreateOpts
+	for _, value := range missing {
+		id := ""
+		for i, rec := range existing {
+			if !matched[i] {
+				matched[i] = true
+				id = rec.ID
+				break
+			}
+		}
+
+		if id == "" {
+			creates = append(creates, RecordCreateOpts{Name: set.Name, Ttl: ttl, Type: set.Type, Value: value, Zone: set.Zone})
+			continue
+		}
+		updates = append(updates, RecordBulkUpdateOpts{ID: id, Type: set.Type, Zone: set.Zone, Name: set.Name, Value: value, Ttl: ttl})
+	}
+
+	if len(updates) > 0 {
+		updated, resp, err := api.BulkUpdate(ctx, updates)
+		if err != nil {
+			return nil, resp, err
+		}
+		if len(updated.FailedRecords) > 0 {
+			return nil, resp, fmt.Errorf("hetzner-dns: %d records failed to update", len(updated.FailedRecords))
+		}
+		result.Records = append(result.Records, updated.Records...)
+	}
+
+	if len(creates) > 0 {
+		created, resp, err := api.BulkCreate(ctx, creates)
+		if err != nil {
+			return nil, resp, err
+		}
+		if len(created.InvalidRecords) > 0 {
+			return nil, resp, fmt.Errorf("hetzner-dns: %d records are invalid", len(created.InvalidRecords))
+		}
+		result.Records = append(result.Records, created.Records...)
+	}
+
+	for i, rec := range existing {
+		if matched[i] {
+			continue
+		}
+
+		resp, err = api.Delete(ctx, rec)
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	for _, rec := range result.Records {
+		result.Values = append(result.Values, rec.Value)
+	}
+
+	return result, resp, nil
+}
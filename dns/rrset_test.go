@@ -0,0 +1,132 @@
+package dns
+
+import (
+	"testing"
+)
+
+func TestGroupRRSets(t *testing.T) {
+	as := newAssert(t)
+
+	sets := GroupRRSets([]*Record{
+		{Name: "www", Type: RecordTypeA, Value: "192.0.2.1", Ttl: 300},
+		{Name: "@", Type: RecordTypeMX, Value: "10 mail"},
+		{Name: "WWW", Type: RecordTypeA, Value: "192.0.2.2", Ttl: 600},
+		{Name: "www", Type: RecordTypeAAAA, Value: "2001:db8::1"},
+	})
+	if !as.EqInt(3, len(sets)) {
+		return
+	}
+
+	as.EqStr("www", sets[0].Name)
+	as.EqInt(300, sets[0].Ttl)
+	as.EqInt(2, len(sets[0].Values))
+	as.EqStr("192.0.2.2", sets[0].Values[1])
+	if warnings := sets[0].Warnings(); as.EqInt(1, len(warnings)) {
+		as.EqStr("www A: records have inconsistent ttls 300, 600", warnings[0])
+	}
+	as.EqInt(0, len(sets[1].Warnings()))
+}
+
+func TestGroupRRSetsSeparatesZones(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	first := api.AddZone("hetzner.com", 3600)
+	second := api.AddZone("hetzner.de", 3600)
+	api.AddRecord(first, "www", RecordTypeA, "192.0.2.1", 0)
+	api.AddRecord(second, "www", RecordTypeA, "192.0.2.2", 0)
+	kept := api.AddRecord(second, "@", RecordTypeA, "192.0.2.3", 0)
+	api.AddRecord(first, "@", RecordTypeA, "192.0.2.4", 0)
+
+	records, _, err := env.Client.Record.List(env.Context, RecordListOpts{})
+	if !as.NoError(err) {
+		return
+	}
+
+	sets := GroupRRSets(records)
+	if !as.EqInt(4, len(sets)) {
+		return
+	}
+	for _, set := range sets {
+		as.EqInt(1, len(set.Records))
+	}
+
+	var set *RRSet
+	for _, s := range sets {
+		if s.Zone.ID == first && s.Name == "@" {
+			set = s
+		}
+	}
+	set.Zone = &Zone{ID: first, Name: "hetzner.com"}
+	set.Values = []string{"192.0.2.5"}
+
+	if _, _, err := env.Client.Record.ReplaceRRSet(env.Context, set); !as.NoError(err) {
+		return
+	}
+	if rec, ok := api.Records[kept]; !ok || rec.Value != "192.0.2.3" {
+		t.Error("expected record of the other zone to be kept")
+	}
+}
+
+func TestRecordClientReplaceRRSet(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	keep := api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.1", 300)
+	update := api.AddRecord(zoneID, "www", RecordTypeA, "192.0.2.2", 300)
+	stale := api.AddRecord(zoneID, "WWW", RecordTypeA, "192.0.2.3", 600)
+	other := api.AddRecord(zoneID, "www", RecordTypeAAAA, "2001:db8::1", 300)
+	zone := &Zone{ID: zoneID, Name: "hetzner.com"}
+
+	set, _, err := env.Client.Record.ReplaceRRSet(env.Context, &RRSet{
+		Zone:   zone,
+		Name:   "www",
+		Type:   RecordTypeA,
+		Ttl:    300,
+		Values: []string{"192.0.2.1", "192.0.2.10"},
+	})
+	if !as.NoError(err) || !as.EqInt(2, len(set.Records)) {
+		return
+	}
+	as.EqStr(keep, set.Records[0].ID)
+	as.EqStr(update, set.Records[1].ID)
+	as.EqStr("192.0.2.10", set.Values[1])
+
+	if _, ok := api.Records[stale]; ok {
+		t.Error("expected stale record to be deleted")
+	}
+	if _, ok := api.Records[other]; !ok {
+		t.Error("expected record of other set to be kept")
+	}
+	as.EqInt(0, api.Calls["POST /records/bulk"])
+
+	set, _, err = env.Client.Record.ReplaceRRSet(env.Context, &RRSet{
+		Zone:   zone,
+		Name:   "www",
+		Type:   RecordTypeA,
+		Values: []string{"192.0.2.1", "192.0.2.10", "192.0.2.11"},
+	})
+	if as.NoError(err) && as.EqInt(3, len(set.Records)) {
+		as.EqInt(2, api.Calls["PUT /records/bulk"])
+		as.EqInt(1, api.Calls["POST /records/bulk"])
+		for _, rec := range set.Records {
+			as.EqInt(0, rec.Ttl)
+		}
+	}
+
+	set, _, err = env.Client.Record.ReplaceRRSet(env.Context, &RRSet{Zone: zone, Name: "www", Type: RecordTypeA})
+	if as.NoError(err) {
+		as.EqInt(0, len(set.Records))
+		as.EqInt(1, len(api.ZoneRecords(zoneID)))
+	}
+
+	_, _, err = env.Client.Record.ReplaceRRSet(env.Context, &RRSet{Zone: zone, Name: "www", Type: "X"})
+	as.Error(err)
+}
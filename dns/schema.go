@@ -55,7 +55,7 @@ func RecordFromSchema(s schema.Record) *Record {
 		Modified: s.Modified,
 		Zone:     &Zone{ID: s.ZoneID},
 		Name:     s.Name,
-		Value:    s.Value,
+		Value:    txtText(s.Type, s.Value),
 		Ttl:      s.Ttl,
 	}
 }
@@ -80,7 +80,7 @@ func RecordEntryFromSchema(s schema.RecordBulkEntry) *RecordEntry {
 		Type:   RecordType(s.Type),
 		ZoneID: s.ZoneID,
 		Name:   s.Name,
-		Value:  s.Value,
+		Value:  txtText(s.Type, s.Value),
 		Ttl:    s.Ttl,
 	}
 }
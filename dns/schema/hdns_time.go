@@ -1,49 +1,167 @@
 package schema
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"strings"
 	"time"
 )
 
+// HdnsTimeLayout is the layout of the times returned by the API, which is
+// the layout of time.Time.String.
+const HdnsTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// hdnsTimeLayouts holds the layouts the API has been seen to return.
+var hdnsTimeLayouts = []string{
+	HdnsTimeLayout,
+	time.RFC3339Nano,
+}
+
 // HdnsTime defines a wrapper for time.Time, to handle the DateTime format(s) used in HCloud DNS API...
 type HdnsTime time.Time
 
+// ParseHdnsTime parses a time in any of the layouts returned by the API, e.g.
+// "2020-04-07 01:24:37 +0000 UTC", the same with fractional seconds, a zone
+// name other than UTC or the monotonic clock reading of time.Time.String,
+// and RFC 3339. An empty string results in the zero time.
+func ParseHdnsTime(s string) (HdnsTime, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return HdnsTime{}, nil
+	}
+
+	// Real example API return value:
+	// [...] "verified": "2020-04-07 01:56:03.196438163 +0000 UTC m=+755.322810452", [...]
+	if i := strings.Index(s, " m="); i >= 0 {
+		s = s[:i]
+	}
+
+	for _, layout := range hdnsTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return HdnsTime(t), nil
+		}
+	}
+
+	// The offset is authoritative, zone names which can not be parsed, like
+	// the numeric names of time.Time.String, are ignored.
+	if fields := strings.Fields(s); len(fields) == 4 {
+		if t, err := time.Parse("2006-01-02 15:04:05.999999999 -0700", strings.Join(fields[:3], " ")); err == nil {
+			return HdnsTime(t), nil
+		}
+	}
+
+	return HdnsTime{}, fmt.Errorf("error while parsing date '%s'", s)
+}
+
+// Time returns the time as time.Time.
+func (ht HdnsTime) Time() time.Time {
+	return time.Time(ht)
+}
+
+// IsZero reports whether the time is the zero time, which is used when the
+// API returns no time.
+func (ht HdnsTime) IsZero() bool {
+	return time.Time(ht).IsZero()
+}
+
+// String returns the time in the layout of the API.
+func (ht HdnsTime) String() string {
+	return ht.Format(HdnsTimeLayout)
+}
+
+// Format returns the time formatted according to layout, see time.Time.Format.
+func (ht HdnsTime) Format(layout string) string {
+	return time.Time(ht).Format(layout)
+}
+
+// Before reports whether the time is before u.
+func (ht HdnsTime) Before(u HdnsTime) bool {
+	return time.Time(ht).Before(time.Time(u))
+}
+
+// After reports whether the time is after u.
+func (ht HdnsTime) After(u HdnsTime) bool {
+	return time.Time(ht).After(time.Time(u))
+}
+
+// Equal reports whether the time is the same instant as u, regardless of
+// their locations.
+func (ht HdnsTime) Equal(u HdnsTime) bool {
+	return time.Time(ht).Equal(time.Time(u))
+}
+
 // UnmarshalJSON is an implementation of encoding/json.Unmarshaler
 func (ht *HdnsTime) UnmarshalJSON(b []byte) error {
-
-	if len(b) == 0 || (len(b) == 2 && b[0] == 34 && b[1] == 34) {
+	s := string(b)
+	if s == "null" {
 		*ht = HdnsTime{}
 		return nil
 	}
 
-	s := strings.Trim(string(b), "\"")
+	t, err := ParseHdnsTime(strings.Trim(s, "\""))
+	if err != nil {
+		return err
+	}
 
-	hdns_time_layout_1 := "2006-01-02 15:04:05 -0700 UTC"
+	*ht = t
+	return nil
+}
 
-	if t, err := time.Parse(hdns_time_layout_1, s); err == nil {
-		*ht = HdnsTime(t)
-		return nil
+// MarshalJSON is an implementation of encoding/json.Marshaler. The time is
+// written in the layout of the API, the zero time as an empty string.
+func (ht HdnsTime) MarshalJSON() ([]byte, error) {
+	text, err := ht.MarshalText()
+	if err != nil {
+		return nil, err
 	}
 
-	// Real example API return value:
-	// [...] "verified": "2020-04-07 01:56:03.196438163 +0000 UTC m=+755.322810452", [...]
-	hdns_time_layout_2 := "2006-01-02 15:04:05.000000000 -0700 UTC" //... m=+755.322810452"
+	return []byte(`"` + string(text) + `"`), nil
+}
 
-	if t, err := time.Parse(hdns_time_layout_2, strings.Split(s, " m=+")[0]); err == nil {
-		*ht = HdnsTime(t)
-		return nil
+// UnmarshalText is an implementation of encoding.TextUnmarshaler
+func (ht *HdnsTime) UnmarshalText(text []byte) error {
+	t, err := ParseHdnsTime(string(text))
+	if err != nil {
+		return err
 	}
 
-	if t, err := time.Parse(time.RFC3339, s); err == nil {
-		*ht = HdnsTime(t)
-		return nil
+	*ht = t
+	return nil
+}
+
+// MarshalText is an implementation of encoding.TextMarshaler
+func (ht HdnsTime) MarshalText() ([]byte, error) {
+	if ht.IsZero() {
+		return []byte{}, nil
 	}
 
-	return fmt.Errorf("error while parsing date '%s'", s)
+	return []byte(ht.String()), nil
 }
 
-// MarshalJSON is an implementation of encoding/json.Marshaler
-func (ht HdnsTime) MarshalJSON() ([]byte, error) {
-	return time.Time(ht).MarshalJSON()
+// Scan is an implementation of database/sql.Scanner
+func (ht *HdnsTime) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*ht = HdnsTime{}
+	case time.Time:
+		*ht = HdnsTime(v)
+	case string:
+		return ht.UnmarshalText([]byte(v))
+	case []byte:
+		return ht.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into HdnsTime", src)
+	}
+
+	return nil
+}
+
+// Value is an implementation of database/sql/driver.Valuer. The zero time is
+// stored as NULL.
+func (ht HdnsTime) Value() (driver.Value, error) {
+	if ht.IsZero() {
+		return nil, nil
+	}
+
+	return time.Time(ht), nil
 }
@@ -1,6 +1,10 @@
 package schema
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
 
 func TestHdnsTimeUnmarshalJSON(t *testing.T) {
 	passUnmarshalTime(t, "2020-04-07 01:24:37 +0000 UTC")
@@ -30,3 +34,126 @@ func failUnMarshalTime(t *testing.T, s string) {
 		t.Errorf("missing expected error")
 	}
 }
+
+func TestParseHdnsTime(t *testing.T) {
+	expected := time.Date(2020, 4, 7, 1, 24, 37, 0, time.UTC)
+
+	for _, s := range []string{
+		"2020-04-07 01:24:37 +0000 UTC",
+		"2020-04-07 03:24:37 +0200 CEST",
+		"2020-04-07 04:24:37 +0300 +03",
+		"2020-04-06 20:24:37 -0500 -0500",
+		"2020-04-07 01:24:37.000000000 +0000 UTC m=+755.322810452",
+		"2020-04-07T03:24:37+02:00",
+	} {
+		ht, err := ParseHdnsTime(s)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", s, err)
+			continue
+		}
+		if !ht.Time().Equal(expected) {
+			t.Errorf("%s: expected %s but got %s", s, expected, ht)
+		}
+	}
+}
+
+func TestHdnsTimeMarshal(t *testing.T) {
+	ht := HdnsTime(time.Date(2020, 4, 7, 1, 24, 37, 500, time.UTC))
+
+	b, err := json.Marshal(ht)
+	if err != nil || string(b) != `"2020-04-07 01:24:37.0000005 +0000 UTC"` {
+		t.Errorf("unexpected json %s: %v", b, err)
+	}
+
+	var parsed HdnsTime
+	if err := json.Unmarshal(b, &parsed); err != nil || !parsed.Equal(ht) {
+		t.Errorf("expected %s but got %s: %v", ht, parsed, err)
+	}
+
+	b, err = json.Marshal(struct{ T HdnsTime }{})
+	if err != nil || string(b) != `{"T":""}` {
+		t.Errorf("unexpected json for zero time %s: %v", b, err)
+	}
+
+	text, _ := ht.MarshalText()
+	parsed = HdnsTime{}
+	if err := parsed.UnmarshalText(text); err != nil || !parsed.Equal(ht) {
+		t.Errorf("expected %s but got %s: %v", ht, parsed, err)
+	}
+
+	if err := json.Unmarshal([]byte("null"), &parsed); err != nil || !parsed.IsZero() {
+		t.Errorf("expected zero time for null: %v", err)
+	}
+}
+
+func TestHdnsTimeCompare(t *testing.T) {
+	a := HdnsTime(time.Date(2020, 4, 7, 1, 24, 37, 0, time.UTC))
+	b := HdnsTime(time.Date(2020, 4, 7, 3, 24, 37, 0, time.FixedZone("CEST", 2*60*60)))
+	c := HdnsTime(time.Date(2020, 4, 8, 0, 0, 0, 0, time.UTC))
+
+	if !a.Equal(b) || a.Before(b) || a.After(b) {
+		t.Error("expected equal times")
+	}
+	if !a.Before(c) || !c.After(a) {
+		t.Error("unexpected order")
+	}
+	if a.Format("2006-01-02") != "2020-04-07" {
+		t.Errorf("unexpected format %s", a.Format("2006-01-02"))
+	}
+}
+
+func TestHdnsTimeSQL(t *testing.T) {
+	ht := HdnsTime(time.Date(2020, 4, 7, 1, 24, 37, 0, time.UTC))
+
+	v, err := ht.Value()
+	if err != nil || !v.(time.Time).Equal(ht.Time()) {
+		t.Errorf("unexpected value %v: %v", v, err)
+	}
+	if v, _ := (HdnsTime{}).Value(); v != nil {
+		t.Errorf("expected nil value for zero time but got %v", v)
+	}
+
+	for _, src := range []interface{}{ht.Time(), ht.String(), []byte(ht.String())} {
+		var scanned HdnsTime
+		if err := scanned.Scan(src); err != nil || !scanned.Equal(ht) {
+			t.Errorf("%T: expected %s but got %s: %v", src, ht, scanned, err)
+		}
+	}
+
+	var scanned HdnsTime
+	if err := scanned.Scan(nil); err != nil || !scanned.IsZero() {
+		t.Errorf("expected zero time for nil: %v", err)
+	}
+	if err := scanned.Scan(42); err == nil {
+		t.Error("missing expected error")
+	}
+}
+
+func FuzzParseHdnsTime(f *testing.F) {
+	for _, s := range []string{
+		"2020-04-07 01:24:37 +0000 UTC",
+		"2020-04-07 01:56:03.196438163 +0000 UTC m=+755.322810452",
+		"2022-12-13 01:37:45.814 +0100 CET",
+		"2020-04-07 04:24:37 +0300 +03",
+		"2022-12-24T20:55:41Z",
+		"",
+	} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		ht, err := ParseHdnsTime(s)
+		if err != nil || ht.IsZero() || ht.Time().Year() < 0 || ht.Time().Year() > 9999 {
+			return
+		}
+
+		// Every parsed time has to survive a round trip through the API layout.
+		parsed, err := ParseHdnsTime(ht.String())
+		if err != nil {
+			t.Fatalf("%q: reparsing %q: %v", s, ht, err)
+		}
+		if !parsed.Equal(ht) {
+			t.Fatalf("%q: expected %s but got %s", s, ht, parsed)
+		}
+	})
+}
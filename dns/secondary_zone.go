@@ -0,0 +1,262 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// DefaultPrimaryServerPort is the port used for a primary server without a port.
+const DefaultPrimaryServerPort = 53
+
+// SecondaryZone represents a zone served by Hetzner as secondary from one or
+// more primary servers.
+type SecondaryZone struct {
+	Zone           *Zone
+	PrimaryServers []*PrimaryServer
+}
+
+// PrimaryServerAddr specifies the address and port of a primary server.
+type PrimaryServerAddr struct {
+	Address string
+	Port    int
+}
+
+func (a PrimaryServerAddr) port() int {
+	if a.Port == 0 {
+		return DefaultPrimaryServerPort
+	}
+	return a.Port
+}
+
+// key returns the normalized host:port of the address used to compare
+// primary servers.
+func (a PrimaryServerAddr) key() string {
+	address := a.Address
+	if ip := net.ParseIP(address); ip != nil {
+		address = ip.String()
+	}
+	return net.JoinHostPort(address, strconv.Itoa(a.port()))
+}
+
+func (a PrimaryServerAddr) validate() error {
+	return validatePrimaryServerAddress(a.Address, a.port())
+}
+
+func validatePrimaryServerAddrs(addrs []PrimaryServerAddr) error {
+	seen := map[string]bool{}
+	for _, addr := range addrs {
+		if err := addr.validate(); err != nil {
+			return fmt.Errorf("primary server %s: %w", addr.Address, err)
+		}
+		if seen[addr.key()] {
+			return fmt.Errorf("duplicate primary server %s", addr.key())
+		}
+		seen[addr.key()] = true
+	}
+
+	return nil
+}
+
+// SecondaryZoneClient is a client for managing secondary zones, combining
+// the zones and primary servers API.
+type SecondaryZoneClient struct {
+	zones   ZoneAPI
+	servers PrimaryServerAPI
+}
+
+// NewSecondaryZoneClient creates a secondary zone client using the given
+// zones and primary servers API.
+func NewSecondaryZoneClient(zones ZoneAPI, servers PrimaryServerAPI) *SecondaryZoneClient {
+	return &SecondaryZoneClient{zones: zones, servers: servers}
+}
+
+// SecondaryZoneCreateOpts specifies options for creating a secondary zone.
+type SecondaryZoneCreateOpts struct {
+	Name           string
+	Ttl            *int
+	PrimaryServers []PrimaryServerAddr
+}
+
+// Validate checks if the options are valid.
+func (o SecondaryZoneCreateOpts) Validate() error {
+	if o.Name == "" {
+		return errors.New("name required")
+	}
+	if len(o.PrimaryServers) == 0 {
+		return errors.New("primary server required")
+	}
+
+	return validatePrimaryServerAddrs(o.PrimaryServers)
+}
+
+// Create creates a new zone and attaches the given primary servers to it.
+// When attaching a primary server fails the created zone is returned along
+// with the error, so it can be fixed or deleted by the caller.
+func (c SecondaryZoneClient) Create(ctx context.Context, opts SecondaryZoneCreateOpts) (*SecondaryZone, *Response, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	zone, resp, err := c.zones.Create(ctx, ZoneCreateOpts{
+		Name: opts.Name,
+		Ttl:  opts.Ttl,
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	secondary := &SecondaryZone{Zone: zone}
+	for _, addr := range opts.PrimaryServers {
+		server, resp, err := c.servers.Create(ctx, PrimaryServerCreateOpts{
+			Address: addr.Address,
+			Port:    addr.port(),
+			ZoneID:  zone.ID,
+		})
+		if err != nil {
+			return secondary, resp, err
+		}
+		secondary.PrimaryServers = append(secondary.PrimaryServers, server)
+	}
+
+	// A zone created in dry run mode doesn't exist and can't be read back.
+	if isDryRunID(zone.ID) {
+		zone.IsSecondaryDNS = true
+		return secondary, resp, nil
+	}
+
+	zone, resp, err = c.zones.GetByID(ctx, zone.ID)
+	if err != nil {
+		return secondary, resp, err
+	}
+	secondary.Zone = zone
+
+	return secondary, resp, nil
+}
+
+// GetByID returns the zone with the given id along with its primary servers.
+func (c SecondaryZoneClient) GetByID(ctx context.Context, id string) (*SecondaryZone, *Response, error) {
+	zone, resp, err := c.zones.GetByID(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	servers, resp, err := c.servers.List(ctx, PrimaryServerListOpts{ZoneID: zone.ID})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &SecondaryZone{Zone: zone, PrimaryServers: servers}, resp, nil
+}
+
+// List returns all zones with the given parameters which are served as
+// secondary zones. The zones are filtered on the client, List therefore
+// requests all pages starting at opts.Page and returns the response of the
+// last page.
+func (c SecondaryZoneClient) List(ctx context.Context, opts ZoneListOpts) ([]*Zone, *Response, error) {
+	zones, resp, err := listAllZones(ctx, c.zones, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	secondaries := make([]*Zone, 0, len(zones))
+	for _, zone := range zones {
+		if zone.IsSecondaryDNS {
+			secondaries = append(secondaries, zone)
+		}
+	}
+
+	return secondaries, resp, nil
+}
+
+// PrimaryServerReconcileResult is returned when reconciling the primary
+// servers of a zone.
+type PrimaryServerReconcileResult struct {
+	Unchanged []*PrimaryServer
+	Created   []*PrimaryServer
+	Updated   []*PrimaryServer
+	Deleted   []*PrimaryServer
+}
+
+// Reconcile converges the primary servers of the zone to the desired
+// addresses. Existing primary servers not desired anymore are reused for new
+// addresses before new primary servers are created; the remainder is deleted.
+func (c SecondaryZoneClient) Reconcile(ctx context.Context, zone *Zone, desired []PrimaryServerAddr) (*PrimaryServerReconcileResult, *Response, error) {
+	if len(desired) == 0 {
+		return nil, nil, errors.New("primary server required")
+	}
+	if err := validatePrimaryServerAddrs(desired); err != nil {
+		return nil, nil, err
+	}
+
+	// A zone created in dry run mode has no primary servers yet.
+	var existing []*PrimaryServer
+	var resp *Response
+	var err error
+	if !isDryRunID(zone.ID) {
+		existing, resp, err = c.servers.List(ctx, PrimaryServerListOpts{ZoneID: zone.ID})
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	result := &PrimaryServerReconcileResult{}
+	wanted := map[string]bool{}
+	for _, addr := range desired {
+		wanted[addr.key()] = true
+	}
+
+	found := map[string]bool{}
+	var stale []*PrimaryServer
+	for _, server := range existing {
+		key := PrimaryServerAddr{Address: server.Address, Port: server.Port}.key()
+		if wanted[key] && !found[key] {
+			found[key] = true
+			result.Unchanged = append(result.Unchanged, server)
+			continue
+		}
+		stale = append(stale, server)
+	}
+
+	for _, addr := range desired {
+		if found[addr.key()] {
+			continue
+		}
+
+		if len(stale) > 0 {
+			server, resp, err := c.servers.Update(ctx, stale[0], PrimaryServerUpdateOpts{
+				Address: addr.Address,
+				Port:    addr.port(),
+				ZoneID:  zone.ID,
+			})
+			if err != nil {
+				return result, resp, err
+			}
+			stale = stale[1:]
+			result.Updated = append(result.Updated, server)
+			continue
+		}
+
+		server, resp, err := c.servers.Create(ctx, PrimaryServerCreateOpts{
+			Address: addr.Address,
+			Port:    addr.port(),
+			ZoneID:  zone.ID,
+		})
+		if err != nil {
+			return result, resp, err
+		}
+		result.Created = append(result.Created, server)
+	}
+
+	for _, server := range stale {
+		resp, err = c.servers.Delete(ctx, server)
+		if err != nil {
+			return result, resp, err
+		}
+		result.Deleted = append(result.Deleted, server)
+	}
+
+	return result, resp, nil
+}
@@ -0,0 +1,194 @@
+package dns
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+// handlePrimaryServers registers an in memory primary servers API on the test env.
+func handlePrimaryServers(env testEnv, servers map[string]*schema.PrimaryServer) {
+	nextID := len(servers)
+
+	env.Mux.HandleFunc(pathPrimaryServers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			var respBody schema.PrimaryServerListResponse
+			for _, server := range servers {
+				if server.ZoneID == r.URL.Query().Get("zone_id") {
+					respBody.PrimaryServers = append(respBody.PrimaryServers, *server)
+				}
+			}
+			json.NewEncoder(w).Encode(respBody) // nolint: errcheck
+		case http.MethodPost:
+			var body schema.PrimaryServerCreateRequest
+			json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+			nextID++
+			server := &schema.PrimaryServer{
+				ID:      fmt.Sprint(nextID),
+				Address: body.Address,
+				Port:    body.Port,
+				ZoneID:  body.ZoneID,
+			}
+			servers[server.ID] = server
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(schema.PrimaryServerResponse{PrimaryServer: *server}) // nolint: errcheck
+		}
+	})
+
+	env.Mux.HandleFunc(pathPrimaryServers+"/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, pathPrimaryServers+"/")
+		server, ok := servers[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			var body schema.PrimaryServerUpdateRequest
+			json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+			server.Address = body.Address
+			server.Port = body.Port
+			json.NewEncoder(w).Encode(schema.PrimaryServerResponse{PrimaryServer: *server}) // nolint: errcheck
+		case http.MethodDelete:
+			delete(servers, id)
+		}
+	})
+}
+
+func TestSecondaryZoneCreate(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	servers := map[string]*schema.PrimaryServer{}
+	handlePrimaryServers(env, servers)
+
+	env.Mux.HandleFunc(pathZones, func(w http.ResponseWriter, r *http.Request) {
+		var body schema.ZoneCreateRequest
+		json.NewDecoder(r.Body).Decode(&body) // nolint: errcheck
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(schema.ZoneResponse{ // nolint: errcheck
+			Zone: schema.Zone{ID: "1", Name: body.Name},
+		})
+	})
+
+	env.Mux.HandleFunc(fmt.Sprintf("%s/1", pathZones), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.ZoneResponse{ // nolint: errcheck
+			Zone: schema.Zone{ID: "1", Name: "hetzner.com", IsSecondaryDNS: len(servers) > 0},
+		})
+	})
+
+	opts := SecondaryZoneCreateOpts{Name: "hetzner.com"}
+	_, _, err := env.Client.SecondaryZone.Create(env.Context, opts)
+	if as.Error(err) {
+		as.EqStr("primary server required", err.Error())
+	}
+
+	opts.PrimaryServers = []PrimaryServerAddr{{Address: "ns.hetzner.com"}}
+	_, _, err = env.Client.SecondaryZone.Create(env.Context, opts)
+	as.Error(err)
+
+	opts.PrimaryServers = []PrimaryServerAddr{{Address: "192.0.2.1"}, {Address: "192.0.2.1", Port: 53}}
+	_, _, err = env.Client.SecondaryZone.Create(env.Context, opts)
+	as.Error(err)
+
+	opts.PrimaryServers = []PrimaryServerAddr{{Address: "192.0.2.1"}, {Address: "2001:db8::1", Port: 5353}}
+	secondary, _, err := env.Client.SecondaryZone.Create(env.Context, opts)
+	if as.NoError(err) {
+		as.EqStr("1", secondary.Zone.ID)
+		as.EqInt(2, len(secondary.PrimaryServers))
+		as.EqInt(DefaultPrimaryServerPort, secondary.PrimaryServers[0].Port)
+		if !secondary.Zone.IsSecondaryDNS {
+			t.Error("expected secondary zone")
+		}
+	}
+}
+
+func TestSecondaryZoneList(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	pages := map[string][]schema.Zone{
+		"1": {{ID: "1", IsSecondaryDNS: true}, {ID: "2"}},
+		"2": {{ID: "3", IsSecondaryDNS: true}, {ID: "4"}},
+	}
+	env.Mux.HandleFunc(pathZones, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		n, _ := strconv.Atoi(page)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct { // nolint: errcheck
+			schema.ZoneListResponse
+			schema.MetaResponse
+		}{
+			ZoneListResponse: schema.ZoneListResponse{Zones: pages[page]},
+			MetaResponse: schema.MetaResponse{Meta: schema.Meta{
+				Pagination: &schema.MetaPagination{Page: n, LastPage: 2, PerPage: 2, TotalEntries: 4},
+			}},
+		})
+	})
+
+	zones, _, err := env.Client.SecondaryZone.List(env.Context, ZoneListOpts{})
+	if as.NoError(err) && as.EqInt(2, len(zones)) {
+		as.EqStr("1", zones[0].ID)
+		as.EqStr("3", zones[1].ID)
+	}
+}
+
+func TestSecondaryZoneReconcile(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	servers := map[string]*schema.PrimaryServer{
+		"1": {ID: "1", Address: "192.0.2.1", Port: 53, ZoneID: "1"},
+		"2": {ID: "2", Address: "192.0.2.2", Port: 53, ZoneID: "1"},
+		"3": {ID: "3", Address: "192.0.2.3", Port: 53, ZoneID: "1"},
+		"4": {ID: "4", Address: "192.0.2.9", Port: 53, ZoneID: "2"},
+	}
+	handlePrimaryServers(env, servers)
+
+	zone := &Zone{ID: "1"}
+	desired := []PrimaryServerAddr{
+		{Address: "192.0.2.1"},
+		{Address: "2001:db8::1"},
+	}
+
+	result, _, err := env.Client.SecondaryZone.Reconcile(env.Context, zone, desired)
+	if as.NoError(err) {
+		as.EqInt(1, len(result.Unchanged))
+		as.EqInt(1, len(result.Updated))
+		as.EqInt(0, len(result.Created))
+		as.EqInt(1, len(result.Deleted))
+	}
+	as.EqInt(3, len(servers))
+
+	desired = append(desired, PrimaryServerAddr{Address: "192.0.2.4", Port: 5353})
+	result, _, err = env.Client.SecondaryZone.Reconcile(env.Context, zone, desired)
+	if as.NoError(err) {
+		as.EqInt(2, len(result.Unchanged))
+		as.EqInt(1, len(result.Created))
+		as.EqInt(0, len(result.Deleted))
+	}
+
+	_, _, err = env.Client.SecondaryZone.Reconcile(env.Context, zone, nil)
+	as.Error(err)
+}
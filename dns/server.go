@@ -0,0 +1,431 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mdns "github.com/miekg/dns"
+)
+
+// ZoneSource loads a zone and its records to be served by a Server.
+type ZoneSource interface {
+	LoadZone(ctx context.Context) (*Zone, []*RecordEntry, error)
+}
+
+// ZoneSourceFunc is an adapter to use an ordinary function as a ZoneSource.
+type ZoneSourceFunc func(ctx context.Context) (*Zone, []*RecordEntry, error)
+
+// LoadZone calls f(ctx).
+func (f ZoneSourceFunc) LoadZone(ctx context.Context) (*Zone, []*RecordEntry, error) {
+	return f(ctx)
+}
+
+// APIZoneSource returns a ZoneSource loading the zone with the given id and
+// its records from the API.
+func APIZoneSource(client *Client, zoneID string) ZoneSource {
+	return ZoneSourceFunc(func(ctx context.Context) (*Zone, []*RecordEntry, error) {
+		zone, _, err := client.Zone.GetByID(ctx, zoneID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		records, _, err := client.Record.listAll(ctx, RecordListOpts{ZoneID: zoneID})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entries := make([]*RecordEntry, 0, len(records))
+		for _, rec := range records {
+			ttl := rec.Ttl
+			entries = append(entries, &RecordEntry{
+				Type:   rec.Type,
+				ZoneID: zoneID,
+				Name:   rec.Name,
+				Value:  rec.Value,
+				Ttl:    &ttl,
+			})
+		}
+
+		return zone, entries, nil
+	})
+}
+
+// FileZoneSource returns a ZoneSource loading the zone from a zone file in
+// BIND format. The file is read again on every refresh.
+func FileZoneSource(origin, path string) ZoneSource {
+	return ZoneSourceFunc(func(ctx context.Context) (*Zone, []*RecordEntry, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+
+		zone := &Zone{Name: strings.TrimSuffix(origin, ".")}
+		var entries []*RecordEntry
+
+		zp := mdns.NewZoneParser(f, mdns.Fqdn(origin), path)
+		for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+			entries = append(entries, entryFromRR(origin, rr))
+		}
+		if err := zp.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		return zone, entries, nil
+	})
+}
+
+// Server is a small authoritative DNS server answering queries over UDP and
+// TCP for the zones loaded from its sources. It is meant for local testing
+// of what the API state would serve, not for production use.
+type Server struct {
+	// Addr is the address to listen on. It defaults to 127.0.0.1:0, which
+	// listens on a random port.
+	Addr string
+	// Sources are the zones to serve.
+	Sources []ZoneSource
+	// RefreshInterval is the interval to reload the zones at. Zones are not
+	// reloaded when zero.
+	RefreshInterval time.Duration
+	// OnRefreshError is called with errors of a periodic refresh, in which
+	// case the previously loaded zones are kept serving.
+	OnRefreshError func(error)
+
+	mu    sync.RWMutex
+	zones map[string]*servedZone
+
+	udp    *mdns.Server
+	tcp    *mdns.Server
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// servedZone holds the records of a zone by lower cased owner name.
+type servedZone struct {
+	origin string
+	soa    mdns.RR
+	names  map[string][]mdns.RR
+}
+
+// Start loads all zones and starts serving them until Close is called.
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.Refresh(ctx); err != nil {
+		return err
+	}
+
+	addr := s.Addr
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	// Listen on the same port for TCP, which matters when a random port is used.
+	host, _, _ := net.SplitHostPort(addr)
+	_, port, _ := net.SplitHostPort(pc.LocalAddr().String())
+	l, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		pc.Close()
+		return err
+	}
+
+	handler := mdns.HandlerFunc(s.serveDNS)
+	s.udp = &mdns.Server{PacketConn: pc, Handler: handler}
+	s.tcp = &mdns.Server{Listener: l, Handler: handler}
+
+	started := make(chan struct{}, 2)
+	s.udp.NotifyStartedFunc = func() { started <- struct{}{} }
+	s.tcp.NotifyStartedFunc = func() { started <- struct{}{} }
+	go s.udp.ActivateAndServe() // nolint: errcheck
+	go s.tcp.ActivateAndServe() // nolint: errcheck
+	<-started
+	<-started
+
+	refreshCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.refreshLoop(refreshCtx)
+
+	return nil
+}
+
+// UDPAddr returns the address the server listens on for UDP queries.
+func (s *Server) UDPAddr() net.Addr {
+	return s.udp.PacketConn.LocalAddr()
+}
+
+// TCPAddr returns the address the server listens on for TCP queries.
+func (s *Server) TCPAddr() net.Addr {
+	return s.tcp.Listener.Addr()
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	if s.cancel == nil {
+		return errors.New("server not started")
+	}
+
+	s.cancel()
+	<-s.done
+
+	uerr := s.udp.Shutdown()
+	terr := s.tcp.Shutdown()
+	if uerr != nil {
+		return uerr
+	}
+
+	return terr
+}
+
+func (s *Server) refreshLoop(ctx context.Context) {
+	defer close(s.done)
+
+	if s.RefreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && s.OnRefreshError != nil {
+				s.OnRefreshError(err)
+			}
+		}
+	}
+}
+
+// Refresh reloads all zones from their sources. The served zones are only
+// replaced when all sources loaded successfully.
+func (s *Server) Refresh(ctx context.Context) error {
+	zones := map[string]*servedZone{}
+	for _, source := range s.Sources {
+		zone, entries, err := source.LoadZone(ctx)
+		if err != nil {
+			return err
+		}
+
+		served, err := newServedZone(zone, entries)
+		if err != nil {
+			return fmt.Errorf("zone %s: %w", zone.Name, err)
+		}
+		zones[served.origin] = served
+	}
+
+	s.mu.Lock()
+	s.zones = zones
+	s.mu.Unlock()
+
+	return nil
+}
+
+func newServedZone(zone *Zone, entries []*RecordEntry) (*servedZone, error) {
+	served := &servedZone{
+		origin: FoldName(mdns.Fqdn(zone.Name)),
+		names:  map[string][]mdns.RR{},
+	}
+
+	for _, e := range entries {
+		ttl := zone.Ttl
+		if e.Ttl != nil && *e.Ttl > 0 {
+			ttl = *e.Ttl
+		}
+
+		rr, err := parseRR(zone.Name, e.Name, ttl, e.Type, e.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		name := FoldName(rr.Header().Name)
+		if rr.Header().Rrtype == mdns.TypeSOA && name == served.origin {
+			served.soa = rr
+		}
+		served.names[name] = append(served.names[name], rr)
+	}
+
+	if served.soa == nil {
+		ttl := zone.Ttl
+		if ttl == 0 {
+			ttl = 3600
+		}
+
+		served.soa = &mdns.SOA{
+			Hdr:     mdns.RR_Header{Name: served.origin, Rrtype: mdns.TypeSOA, Class: mdns.ClassINET, Ttl: uint32(ttl)},
+			Ns:      "ns1." + served.origin,
+			Mbox:    "hostmaster." + served.origin,
+			Serial:  1,
+			Refresh: 86400,
+			Retry:   10800,
+			Expire:  3600000,
+			Minttl:  uint32(ttl),
+		}
+		served.names[served.origin] = append(served.names[served.origin], served.soa)
+	}
+
+	return served, nil
+}
+
+// findZone returns the served zone with the longest origin containing name.
+func (s *Server) findZone(name string) *servedZone {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for labels := mdns.SplitDomainName(name); ; labels = labels[1:] {
+		if zone, ok := s.zones[mdns.Fqdn(strings.Join(labels, "."))]; ok {
+			return zone
+		}
+		if len(labels) == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *Server) serveDNS(w mdns.ResponseWriter, r *mdns.Msg) {
+	m := new(mdns.Msg)
+	m.SetReply(r)
+
+	if len(r.Question) != 1 {
+		m.SetRcode(r, mdns.RcodeFormatError)
+		w.WriteMsg(m) // nolint: errcheck
+		return
+	}
+
+	q := r.Question[0]
+	name := FoldName(q.Name)
+	zone := s.findZone(name)
+	if zone == nil || q.Qclass != mdns.ClassINET {
+		m.SetRcode(r, mdns.RcodeRefused)
+		w.WriteMsg(m) // nolint: errcheck
+		return
+	}
+
+	zone.answer(m, name, q.Qtype)
+	w.WriteMsg(m) // nolint: errcheck
+}
+
+// answer fills the response m for a query of name and qtype within the zone.
+func (z *servedZone) answer(m *mdns.Msg, name string, qtype uint16) {
+	// Names below a delegation are answered with a referral.
+	if ns := z.delegation(name); ns != nil && !(qtype == mdns.TypeDS && ns[0].Header().Name == name) {
+		m.Ns = append(m.Ns, ns...)
+		return
+	}
+
+	m.Authoritative = true
+	for depth := 0; depth < 8; depth++ {
+		rrs, ok := z.lookup(name)
+		if !ok {
+			if depth == 0 {
+				m.Rcode = mdns.RcodeNameError
+			}
+			m.Ns = append(m.Ns, z.soa)
+			return
+		}
+
+		var cname *mdns.CNAME
+		found := false
+		for _, rr := range rrs {
+			switch {
+			case qtype == mdns.TypeANY || rr.Header().Rrtype == qtype:
+				m.Answer = append(m.Answer, withName(rr, name))
+				found = true
+			case rr.Header().Rrtype == mdns.TypeCNAME:
+				cname = rr.(*mdns.CNAME)
+			}
+		}
+
+		if found {
+			return
+		}
+		if cname == nil {
+			m.Ns = append(m.Ns, z.soa)
+			return
+		}
+
+		m.Answer = append(m.Answer, withName(cname, name))
+		name = FoldName(cname.Target)
+		if !mdns.IsSubDomain(z.origin, name) {
+			return
+		}
+	}
+}
+
+// lookup returns the records of name, falling back to a matching wildcard.
+func (z *servedZone) lookup(name string) ([]mdns.RR, bool) {
+	if rrs, ok := z.names[name]; ok {
+		return rrs, true
+	}
+
+	// Empty non-terminals exist without records.
+	for owner := range z.names {
+		if strings.HasSuffix(owner, "."+name) {
+			return nil, true
+		}
+	}
+
+	labels := mdns.SplitDomainName(name)
+	for i := 1; i < len(labels); i++ {
+		parent := mdns.Fqdn(strings.Join(labels[i:], "."))
+		if !mdns.IsSubDomain(z.origin, parent) {
+			break
+		}
+		if rrs, ok := z.names["*."+parent]; ok {
+			return rrs, true
+		}
+		if _, ok := z.names[parent]; ok {
+			break
+		}
+	}
+
+	return nil, false
+}
+
+// delegation returns the NS records of the closest delegation below the
+// apex containing name, if any.
+func (z *servedZone) delegation(name string) []mdns.RR {
+	labels := mdns.SplitDomainName(name)
+	for i := len(labels) - 1; i >= 0; i-- {
+		owner := mdns.Fqdn(strings.Join(labels[i:], "."))
+		if owner == z.origin || !mdns.IsSubDomain(z.origin, owner) {
+			continue
+		}
+
+		var ns []mdns.RR
+		for _, rr := range z.names[owner] {
+			if rr.Header().Rrtype == mdns.TypeNS {
+				ns = append(ns, rr)
+			}
+		}
+		if len(ns) > 0 {
+			sort.Slice(ns, func(i, j int) bool { return ns[i].String() < ns[j].String() })
+			return ns
+		}
+	}
+
+	return nil
+}
+
+// withName returns rr with the owner name replaced, used for wildcard answers.
+func withName(rr mdns.RR, name string) mdns.RR {
+	if strings.EqualFold(rr.Header().Name, name) {
+		return rr
+	}
+
+	rr = mdns.Copy(rr)
+	rr.Header().Name = name
+	return rr
+}
@@ -0,0 +1,189 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	mdns "github.com/miekg/dns"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+func startTestServer(t *testing.T, sources ...ZoneSource) *Server {
+	srv := &Server{Sources: sources}
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() }) // nolint: errcheck
+
+	return srv
+}
+
+func query(t *testing.T, srv *Server, network, name string, qtype uint16) *mdns.Msg {
+	addr := srv.UDPAddr().String()
+	if network == "tcp" {
+		addr = srv.TCPAddr().String()
+	}
+
+	m := new(mdns.Msg)
+	m.SetQuestion(name, qtype)
+
+	c := &mdns.Client{Net: network, Timeout: time.Second}
+	resp, _, err := c.Exchange(m, addr)
+	if err != nil {
+		t.Fatalf("query %s %s failed: %v", name, mdns.TypeToString[qtype], err)
+	}
+
+	return resp
+}
+
+func TestServer(t *testing.T) {
+	as := newAssert(t)
+
+	source := ZoneSourceFunc(func(ctx context.Context) (*Zone, []*RecordEntry, error) {
+		return &Zone{Name: "hetzner.com", Ttl: 300}, []*RecordEntry{
+			{Type: RecordTypeA, Name: "@", Value: "192.0.2.1"},
+			{Type: RecordTypeAAAA, Name: "www", Value: "2001:db8::1"},
+			{Type: RecordTypeCNAME, Name: "ftp", Value: "www"},
+			{Type: RecordTypeMX, Name: "@", Value: "10 mail"},
+			{Type: RecordTypeTXT, Name: "@", Value: "v=spf1 mx -all"},
+			{Type: RecordTypeA, Name: "*.apps", Value: "192.0.2.2"},
+			{Type: RecordTypeA, Name: "deep.empty", Value: "192.0.2.3"},
+			{Type: RecordTypeNS, Name: "sub", Value: "ns1.example.com."},
+			{Type: RecordTypeCAA, Name: "@", Value: `0 issue "letsencrypt.org"`},
+			{Type: RecordTypeSRV, Name: "_sip._tcp", Value: "10 60 5060 sip"},
+		}, nil
+	})
+
+	srv := startTestServer(t, source)
+
+	for _, network := range []string{"udp", "tcp"} {
+		resp := query(t, srv, network, "hetzner.com.", mdns.TypeA)
+		if as.EqInt(mdns.RcodeSuccess, resp.Rcode) && as.EqInt(1, len(resp.Answer)) {
+			as.EqStr("192.0.2.1", resp.Answer[0].(*mdns.A).A.String())
+			if !resp.Authoritative {
+				t.Error("expected authoritative answer")
+			}
+		}
+	}
+
+	resp := query(t, srv, "udp", "FTP.hetzner.com.", mdns.TypeAAAA)
+	if as.EqInt(2, len(resp.Answer)) {
+		as.EqStr("www.hetzner.com.", resp.Answer[0].(*mdns.CNAME).Target)
+		as.EqStr("2001:db8::1", resp.Answer[1].(*mdns.AAAA).AAAA.String())
+	}
+
+	resp = query(t, srv, "udp", "hetzner.com.", mdns.TypeTXT)
+	if as.EqInt(1, len(resp.Answer)) {
+		as.EqStr("v=spf1 mx -all", resp.Answer[0].(*mdns.TXT).Txt[0])
+	}
+
+	resp = query(t, srv, "udp", "hetzner.com.", mdns.TypeSOA)
+	as.EqInt(1, len(resp.Answer))
+
+	resp = query(t, srv, "udp", "hetzner.com.", mdns.TypeCAA)
+	as.EqInt(1, len(resp.Answer))
+
+	resp = query(t, srv, "udp", "_sip._tcp.hetzner.com.", mdns.TypeSRV)
+	as.EqInt(1, len(resp.Answer))
+
+	resp = query(t, srv, "udp", "foo.apps.hetzner.com.", mdns.TypeA)
+	if as.EqInt(1, len(resp.Answer)) {
+		as.EqStr("foo.apps.hetzner.com.", resp.Answer[0].Header().Name)
+	}
+
+	resp = query(t, srv, "udp", "empty.hetzner.com.", mdns.TypeA)
+	as.EqInt(mdns.RcodeSuccess, resp.Rcode)
+	as.EqInt(0, len(resp.Answer))
+
+	resp = query(t, srv, "udp", "www.hetzner.com.", mdns.TypeA)
+	as.EqInt(mdns.RcodeSuccess, resp.Rcode)
+	as.EqInt(0, len(resp.Answer))
+	as.EqInt(1, len(resp.Ns))
+
+	resp = query(t, srv, "udp", "missing.hetzner.com.", mdns.TypeA)
+	as.EqInt(mdns.RcodeNameError, resp.Rcode)
+
+	resp = query(t, srv, "udp", "host.sub.hetzner.com.", mdns.TypeA)
+	if as.EqInt(1, len(resp.Ns)) {
+		as.EqStr("ns1.example.com.", resp.Ns[0].(*mdns.NS).Ns)
+		if resp.Authoritative {
+			t.Error("unexpected authoritative referral")
+		}
+	}
+
+	resp = query(t, srv, "udp", "hetzner.de.", mdns.TypeA)
+	as.EqInt(mdns.RcodeRefused, resp.Rcode)
+}
+
+func TestServerRefresh(t *testing.T) {
+	as := newAssert(t)
+
+	var mu sync.Mutex
+	value := "192.0.2.1"
+	source := ZoneSourceFunc(func(ctx context.Context) (*Zone, []*RecordEntry, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return &Zone{Name: "hetzner.com"}, []*RecordEntry{{Type: RecordTypeA, Name: "www", Value: value}}, nil
+	})
+
+	srv := startTestServer(t, source)
+
+	mu.Lock()
+	value = "192.0.2.2"
+	mu.Unlock()
+
+	if as.NoError(srv.Refresh(context.Background())) {
+		resp := query(t, srv, "udp", "www.hetzner.com.", mdns.TypeA)
+		if as.EqInt(1, len(resp.Answer)) {
+			as.EqStr("192.0.2.2", resp.Answer[0].(*mdns.A).A.String())
+		}
+	}
+}
+
+func TestServerSources(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	env.Mux.HandleFunc(fmt.Sprintf("%s/1", pathZones), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.ZoneResponse{ // nolint: errcheck
+			Zone: schema.Zone{ID: "1", Name: "hetzner.com", Ttl: 300},
+		})
+	})
+
+	env.Mux.HandleFunc(pathRecords, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.RecordListResponse{ // nolint: errcheck
+			Records: []schema.Record{
+				{ID: "1", Type: "A", Name: "www", Value: "192.0.2.1", ZoneID: "1"},
+			},
+		})
+	})
+
+	path := filepath.Join(t.TempDir(), "hetzner.de.zone")
+	zoneFile := "$TTL 300\n@ IN SOA ns1 hostmaster 1 86400 10800 3600000 300\nwww IN A 192.0.2.2\n"
+	if err := os.WriteFile(path, []byte(zoneFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := startTestServer(t, APIZoneSource(env.Client, "1"), FileZoneSource("hetzner.de", path))
+
+	resp := query(t, srv, "udp", "www.hetzner.com.", mdns.TypeA)
+	if as.EqInt(1, len(resp.Answer)) {
+		as.EqStr("192.0.2.1", resp.Answer[0].(*mdns.A).A.String())
+		as.EqInt(300, int(resp.Answer[0].Header().Ttl))
+	}
+
+	resp = query(t, srv, "udp", "www.hetzner.de.", mdns.TypeA)
+	if as.EqInt(1, len(resp.Answer)) {
+		as.EqStr("192.0.2.2", resp.Answer[0].(*mdns.A).A.String())
+	}
+}
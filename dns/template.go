@@ -0,0 +1,276 @@
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ZoneTemplate is a parameterized set of records, e.g. the records of an
+// email provider, which can be expanded for any zone.
+//
+// Text is a text/template which must expand to records in zone file syntax,
+// one per line as "name [ttl] [IN] type value". Names are relative to the
+// zone, "@" is the zone apex. Empty lines and lines starting with ";" are
+// ignored. Records without ttl use the default ttl of the zone.
+//
+// The template is executed with the zone name as .Zone and the parameters,
+// e.g. .DKIMKey. Besides the functions of text/template, the functions
+// default, lower and replace are available:
+//
+//	{{ default "none" .Policy }}
+//	{{ replace .Zone "." "-" }}
+type ZoneTemplate struct {
+	Name        string
+	Description string
+	Text        string
+	// Required holds the parameters which must be given.
+	Required []string
+	// Defaults holds the values of the parameters which are not given.
+	// Parameters which are neither required nor have a default must be
+	// given as well.
+	Defaults map[string]string
+}
+
+var templateFuncs = template.FuncMap{
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	"lower":   strings.ToLower,
+	"replace": strings.ReplaceAll,
+}
+
+// Expand returns the options to create the records of the template in the
+// zone with the given parameters.
+func (t *ZoneTemplate) Expand(zone *Zone, params map[string]string) ([]RecordCreateOpts, error) {
+	if zone == nil || zone.Name == "" {
+		return nil, errors.New("zone name required")
+	}
+
+	data := map[string]string{}
+	for k, v := range t.Defaults {
+		data[k] = v
+	}
+	for k, v := range params {
+		data[k] = v
+	}
+	for _, name := range t.Required {
+		if data[name] == "" {
+			return nil, fmt.Errorf("template %s: parameter %s required", t.Name, name)
+		}
+	}
+	data["Zone"] = zone.Name
+
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Funcs(templateFuncs).Parse(t.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	var opts []RecordCreateOpts
+	scanner := bufio.NewScanner(&buf)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		opt, err := parseTemplateRecord(zone, text)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: line %d: %w", t.Name, line, err)
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts, scanner.Err()
+}
+
+// parseTemplateRecord parses an expanded record line of a template.
+func parseTemplateRecord(zone *Zone, line string) (RecordCreateOpts, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return RecordCreateOpts{}, fmt.Errorf("invalid record %q", line)
+	}
+
+	name, rest := fields[0], fields[1:]
+	var ttl *int
+	if t, err := strconv.Atoi(rest[0]); err == nil {
+		ttl = &t
+		rest = rest[1:]
+	}
+	if len(rest) > 0 && strings.EqualFold(rest[0], "IN") {
+		rest = rest[1:]
+	}
+	if len(rest) < 2 {
+		return RecordCreateOpts{}, fmt.Errorf("invalid record %q", line)
+	}
+
+	typ := RecordType(strings.ToUpper(rest[0]))
+	if !typ.valid() || typ == RecordTypeSOA {
+		return RecordCreateOpts{}, fmt.Errorf("unsupported record type %s", rest[0])
+	}
+
+	// The value is the remainder of the line, keeping the spaces of quoted
+	// strings.
+	value := skipFields(line, len(fields)-len(rest)+1)
+
+	rrTtl := 0
+	if ttl != nil {
+		rrTtl = *ttl
+	}
+	rr, err := parseRR(zone.Name, name, rrTtl, typ, value)
+	if err != nil {
+		return RecordCreateOpts{}, err
+	}
+	entry := entryFromRR(zone.Name, rr)
+
+	return RecordCreateOpts{
+		Name:  entry.Name,
+		Ttl:   ttl,
+		Type:  typ,
+		Value: entry.Value,
+		Zone:  zone,
+	}, nil
+}
+
+// skipFields returns s without its first n fields.
+func skipFields(s string, n int) string {
+	for i := 0; i < n; i++ {
+		s = strings.TrimLeft(s, " \t")
+		if end := strings.IndexAny(s, " \t"); end >= 0 {
+			s = s[end:]
+		} else {
+			s = ""
+		}
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// ApplyTemplate creates the records of the template in the zone with the
+// given parameters.
+func (c RecordClient) ApplyTemplate(ctx context.Context, zone *Zone, tmpl *ZoneTemplate, params map[string]string) (*RecordBulkCreateResponse, *Response, error) {
+	opts, err := tmpl.Expand(zone, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.BulkCreate(ctx, opts)
+}
+
+// builtinTemplates holds the templates returned by BuiltinTemplate.
+var builtinTemplates = map[string]*ZoneTemplate{
+	"google-workspace": {
+		Name:        "google-workspace",
+		Description: "Google Workspace mail with SPF and, given the DKIMKey, DKIM",
+		Defaults:    map[string]string{"DKIMSelector": "google", "DKIMKey": ""},
+		Text: `@ MX 1 smtp.google.com.
+@ TXT "v=spf1 include:_spf.google.com ~all"
+{{ if .DKIMKey }}{{ .DKIMSelector }}._domainkey TXT "v=DKIM1; k=rsa; p={{ .DKIMKey }}"{{ end }}
+`,
+	},
+	"microsoft-365": {
+		Name:        "microsoft-365",
+		Description: "Microsoft 365 mail with autodiscover, SPF and, given the Tenant, DKIM",
+		Defaults:    map[string]string{"Tenant": ""},
+		Text: `@ MX 0 {{ replace .Zone "." "-" }}.mail.protection.outlook.com.
+@ TXT "v=spf1 include:spf.protection.outlook.com -all"
+autodiscover CNAME autodiscover.outlook.com.
+{{ if .Tenant }}selector1._domainkey CNAME selector1-{{ replace .Zone "." "-" }}._domainkey.{{ .Tenant }}.onmicrosoft.com.
+selector2._domainkey CNAME selector2-{{ replace .Zone "." "-" }}._domainkey.{{ .Tenant }}.onmicrosoft.com.{{ end }}
+`,
+	},
+	"fastmail": {
+		Name:        "fastmail",
+		Description: "Fastmail mail with SPF and DKIM",
+		Text: `@ MX 10 in1-smtp.messagingengine.com.
+@ MX 20 in2-smtp.messagingengine.com.
+@ TXT "v=spf1 include:spf.messagingengine.com ?all"
+fm1._domainkey CNAME fm1.{{ .Zone }}.dkim.fmhosted.com.
+fm2._domainkey CNAME fm2.{{ .Zone }}.dkim.fmhosted.com.
+fm3._domainkey CNAME fm3.{{ .Zone }}.dkim.fmhosted.com.
+`,
+	},
+	"proton-mail": {
+		Name:        "proton-mail",
+		Description: "Proton Mail with domain verification, SPF and DKIM",
+		Required:    []string{"VerificationToken", "DKIMID"},
+		Text: `@ TXT "protonmail-verification={{ .VerificationToken }}"
+@ MX 10 mail.protonmail.ch.
+@ MX 20 mailsec.protonmail.ch.
+@ TXT "v=spf1 include:_spf.protonmail.ch ~all"
+protonmail._domainkey CNAME protonmail.domainkey.{{ .DKIMID }}.domains.proton.ch.
+protonmail2._domainkey CNAME protonmail2.domainkey.{{ .DKIMID }}.domains.proton.ch.
+protonmail3._domainkey CNAME protonmail3.domainkey.{{ .DKIMID }}.domains.proton.ch.
+`,
+	},
+	"dmarc": {
+		Name:        "dmarc",
+		Description: "DMARC policy with optional aggregate reports to the Report address",
+		Defaults:    map[string]string{"Policy": "none", "Report": ""},
+		Text: `_dmarc TXT "v=DMARC1; p={{ .Policy }}{{ if .Report }}; rua=mailto:{{ .Report }}{{ end }}"
+`,
+	},
+	"caa": {
+		Name:        "caa",
+		Description: "CAA records allowing the Issuer to issue certificates, with optional incident reports to the IODEF address",
+		Defaults:    map[string]string{"Issuer": "letsencrypt.org", "IODEF": ""},
+		Text: `@ CAA 0 issue "{{ .Issuer }}"
+{{ if .IODEF }}@ CAA 0 iodef "mailto:{{ .IODEF }}"{{ end }}
+`,
+	},
+	"web": {
+		Name:        "web",
+		Description: "Website on the IPv4 and optional IPv6 address with www as alias of the apex",
+		Required:    []string{"IPv4"},
+		Defaults:    map[string]string{"IPv6": ""},
+		Text: `@ A {{ .IPv4 }}
+{{ if .IPv6 }}@ AAAA {{ .IPv6 }}{{ end }}
+www CNAME {{ .Zone }}.
+`,
+	},
+}
+
+// BuiltinTemplate returns a copy of the built-in template with the given
+// name, see BuiltinTemplates.
+func BuiltinTemplate(name string) (*ZoneTemplate, error) {
+	t, ok := builtinTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template %s", name)
+	}
+
+	clone := *t
+	clone.Required = append([]string(nil), t.Required...)
+	clone.Defaults = make(map[string]string, len(t.Defaults))
+	for k, v := range t.Defaults {
+		clone.Defaults[k] = v
+	}
+
+	return &clone, nil
+}
+
+// BuiltinTemplates returns the names of the built-in templates:
+// google-workspace, microsoft-365, fastmail and proton-mail for email
+// providers, dmarc, caa and web.
+func BuiltinTemplates() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
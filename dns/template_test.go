@@ -0,0 +1,120 @@
+package dns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestZoneTemplateExpand(t *testing.T) {
+	as := newAssert(t)
+
+	tmpl := &ZoneTemplate{
+		Name:     "test",
+		Required: []string{"IP"},
+		Defaults: map[string]string{"Selector": "mail"},
+		Text: `; web
+@ 300 IN A {{ .IP }}
+www	CNAME {{ .Zone }}.
+
+{{ .Selector }}._domainkey TXT "v=DKIM1; p={{ .Key }}"
+`,
+	}
+	zone := &Zone{ID: "zone1", Name: "hetzner.com"}
+
+	opts, err := tmpl.Expand(zone, map[string]string{"IP": "192.0.2.1", "Key": "abc"})
+	if !as.NoError(err) || !as.EqInt(3, len(opts)) {
+		return
+	}
+
+	as.EqStr("@", opts[0].Name)
+	as.EqStr(string(RecordTypeA), string(opts[0].Type))
+	as.EqStr("192.0.2.1", opts[0].Value)
+	as.EqInt(300, *opts[0].Ttl)
+	if opts[0].Zone != zone {
+		t.Error("expected zone of the options")
+	}
+
+	as.EqStr("www", opts[1].Name)
+	as.EqStr("hetzner.com.", opts[1].Value)
+	if opts[1].Ttl != nil {
+		t.Errorf("unexpected ttl %d", *opts[1].Ttl)
+	}
+
+	as.EqStr("mail._domainkey", opts[2].Name)
+	as.EqStr("v=DKIM1; p=abc", opts[2].Value)
+
+	_, err = tmpl.Expand(zone, map[string]string{"Key": "abc"})
+	if err == nil || !strings.Contains(err.Error(), "parameter IP required") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// Parameters without default must be given.
+	_, err = tmpl.Expand(zone, map[string]string{"IP": "192.0.2.1"})
+	as.Error(err)
+
+	_, err = tmpl.Expand(zone, map[string]string{"IP": "not an ip", "Key": "abc"})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	params := map[string]string{
+		"DKIMKey":           strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA", 9),
+		"Tenant":            "hetzner",
+		"VerificationToken": "token",
+		"DKIMID":            "id",
+		"Report":            "dmarc@hetzner.com",
+		"IODEF":             "security@hetzner.com",
+		"IPv4":              "192.0.2.1",
+		"IPv6":              "2001:db8::1",
+	}
+	zone := &Zone{ID: "zone1", Name: "hetzner.com"}
+
+	for _, name := range BuiltinTemplates() {
+		tmpl, err := BuiltinTemplate(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opts, err := tmpl.Expand(zone, params)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+		} else if len(opts) == 0 {
+			t.Errorf("%s: no records", name)
+		}
+	}
+
+	tmpl, _ := BuiltinTemplate("microsoft-365")
+	opts, err := tmpl.Expand(zone, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 3 || opts[0].Value != "0 hetzner-com.mail.protection.outlook.com." {
+		t.Errorf("unexpected records %+v", opts)
+	}
+
+	if _, err := BuiltinTemplate("unknown"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestRecordClientApplyTemplate(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	zone := &Zone{ID: zoneID, Name: "hetzner.com"}
+
+	tmpl, _ := BuiltinTemplate("fastmail")
+	resp, _, err := env.Client.Record.ApplyTemplate(env.Context, zone, tmpl, nil)
+	if !as.NoError(err) {
+		return
+	}
+	as.EqInt(6, len(resp.Records))
+	as.EqInt(6, len(api.ZoneRecords(zoneID)))
+	as.EqInt(1, api.Calls["POST /records/bulk"])
+}
@@ -0,0 +1,149 @@
+package dns
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTXTStringLength is the maximum length in bytes of a character string of
+// a TXT record. Longer values are split into multiple strings.
+const MaxTXTStringLength = 255
+
+// EncodeTXT encodes the text of a TXT record as the API and zone files
+// expect it: split into character strings of at most MaxTXTStringLength
+// bytes, each quoted and with quotes, backslashes and control characters
+// escaped. Strings are not split within UTF-8 sequences.
+//
+// The client encodes TXT values when creating and updating records, see
+// DecodeTXT for the values it reads.
+func EncodeTXT(text string) string {
+	strs := splitTXT(text)
+	quoted := make([]string, len(strs))
+	for i, s := range strs {
+		quoted[i] = quoteTXT(s)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// DecodeTXT returns the text of an encoded TXT value, joining its character
+// strings and resolving their escapes. Values which are not a sequence of
+// quoted strings, like plain text, are returned unchanged, so DecodeTXT
+// can be applied to any value.
+//
+// The client decodes the values of the TXT records it reads, so Value holds
+// the text of the record.
+func DecodeTXT(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, `"`) {
+		return value
+	}
+
+	var b strings.Builder
+	for trimmed != "" {
+		s, rest, ok := unquoteTXTString(trimmed)
+		if !ok {
+			return value
+		}
+		b.WriteString(s)
+		trimmed = strings.TrimLeft(rest, " \t")
+	}
+
+	return b.String()
+}
+
+// txtText returns the value of a record as read from the API, decoding the
+// text of TXT records.
+func txtText(typ string, value string) string {
+	if RecordType(typ) != RecordTypeTXT {
+		return value
+	}
+
+	return DecodeTXT(value)
+}
+
+// splitTXT splits text into character strings.
+func splitTXT(text string) []string {
+	var strs []string
+	for len(text) > MaxTXTStringLength {
+		cut := MaxTXTStringLength
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = MaxTXTStringLength
+		}
+
+		strs = append(strs, text[:cut])
+		text = text[cut:]
+	}
+
+	return append(strs, text)
+}
+
+// quoteTXT quotes a character string.
+func quoteTXT(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c == 0x7f:
+			b.WriteByte('\\')
+			b.WriteString(strconv.Itoa(int(c) + 1000)[1:])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// unquoteTXTString returns the unescaped content of the quoted character
+// string at the beginning of s and the remainder of s. Escapes are either a
+// backslash followed by three decimal digits or by the escaped character.
+func unquoteTXTString(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", s, false
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+3 < len(s) && isDigits(s[i+1:i+4]) {
+				n, _ := strconv.Atoi(s[i+1 : i+4])
+				if n > 255 {
+					return "", s, false
+				}
+				b.WriteByte(byte(n))
+				i += 3
+			} else if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", s, false
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,111 @@
+package dns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeTXT(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	// The umlaut at byte 255 is not split.
+	umlaut := strings.Repeat("a", 254) + "ü" + "b"
+
+	tests := map[string]struct {
+		text    string
+		encoded string
+	}{
+		"empty":      {"", `""`},
+		"plain":      {"v=spf1 -all", `"v=spf1 -all"`},
+		"quotes":     {`say "hi"`, `"say \"hi\""`},
+		"backslash":  {`a\b`, `"a\\b"`},
+		"semicolons": {"v=DKIM1; k=rsa; p=abc", `"v=DKIM1; k=rsa; p=abc"`},
+		"control":    {"a\tb\n", `"a\009b\010"`},
+		"exact":      {strings.Repeat("a", 255), `"` + strings.Repeat("a", 255) + `"`},
+		"long":       {long, `"` + long[:255] + `" "` + long[255:] + `"`},
+		"utf-8":      {umlaut, `"` + umlaut[:254] + `" "` + umlaut[254:] + `"`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded := EncodeTXT(tt.text)
+			if encoded != tt.encoded {
+				t.Errorf("expected %s but got %s", tt.encoded, encoded)
+			}
+			if decoded := DecodeTXT(encoded); decoded != tt.text {
+				t.Errorf("expected %q after round trip but got %q", tt.text, decoded)
+			}
+
+			// The encoded value is a valid zone file value with the same text.
+			rr, err := parseRR("hetzner.com", "@", 300, RecordTypeTXT, encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text := DecodeTXT(rrValue(rr)); text != tt.text {
+				t.Errorf("expected %q from zone file but got %q", tt.text, text)
+			}
+		})
+	}
+}
+
+func TestDecodeTXT(t *testing.T) {
+	tests := map[string]string{
+		`v=spf1 -all`:               "v=spf1 -all",
+		`"v=DKIM1; " "p=abc"`:       "v=DKIM1; p=abc",
+		`"a\"b" "c\\d"`:             `a"bc\d`,
+		`"a\059b"`:                  "a;b",
+		`"a\;b"`:                    "a;b",
+		`  "padded"  `:              "padded",
+		`"unterminated`:             `"unterminated`,
+		`"quoted" and plain`:        `"quoted" and plain`,
+		`"out of range \256"`:       `"out of range \256"`,
+		`plain "with quotes" in it`: `plain "with quotes" in it`,
+	}
+
+	for value, expected := range tests {
+		if text := DecodeTXT(value); text != expected {
+			t.Errorf("%s: expected %q but got %q", value, expected, text)
+		}
+	}
+}
+
+func TestRecordTXTEncoding(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	api := newFakeAPI(env)
+	zoneID := api.AddZone("hetzner.com", 3600)
+	zone := &Zone{ID: zoneID, Name: "hetzner.com"}
+
+	key := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 12)
+	rec, _, err := env.Client.Record.Create(env.Context, RecordCreateOpts{Name: "mail._domainkey", Type: RecordTypeTXT, Value: key, Zone: zone})
+	if !as.NoError(err) {
+		return
+	}
+
+	// The API receives the split value and the client returns the text.
+	as.EqStr(EncodeTXT(key), api.Records[rec.ID].Value)
+	if !strings.Contains(api.Records[rec.ID].Value, `" "`) {
+		t.Errorf("expected multiple strings in %s", api.Records[rec.ID].Value)
+	}
+	as.EqStr(key, rec.Value)
+
+	read, _, err := env.Client.Record.GetByID(env.Context, rec.ID)
+	if as.NoError(err) {
+		as.EqStr(key, read.Value)
+	}
+
+	// Values which are already encoded are not encoded twice.
+	updated, _, err := env.Client.Record.Update(env.Context, rec, RecordUpdateOpts{Name: "mail._domainkey", Type: RecordTypeTXT, Value: `"say \"hi\""`, Zone: zone})
+	if as.NoError(err) {
+		as.EqStr(`"say \"hi\""`, api.Records[rec.ID].Value)
+		as.EqStr(`say "hi"`, updated.Value)
+	}
+
+	// Other types are sent unchanged.
+	rec, _, err = env.Client.Record.Create(env.Context, RecordCreateOpts{Name: "@", Type: RecordTypeCAA, Value: `0 issue "letsencrypt.org"`, Zone: zone})
+	if as.NoError(err) {
+		as.EqStr(`0 issue "letsencrypt.org"`, api.Records[rec.ID].Value)
+	}
+}
@@ -0,0 +1,86 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrNoTxtVerification is returned when a zone has no TXT verification token.
+var ErrNoTxtVerification = errors.New("hetzner-dns: zone has no txt verification")
+
+// TXTResolver looks up the TXT records of a domain. *net.Resolver satisfies
+// this interface.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// TxtVerificationName returns the fully qualified name, with trailing dot,
+// at which the TXT verification record has to be published.
+func (z *Zone) TxtVerificationName() (string, error) {
+	if z.TxtVerification == nil || z.TxtVerification.Token == "" {
+		return "", ErrNoTxtVerification
+	}
+
+	return ToFQDN(z.Name, z.TxtVerification.Name), nil
+}
+
+// TxtVerificationRecord renders the TXT verification record in zone file
+// format, ready to be published at the current DNS provider.
+func (z *Zone) TxtVerificationRecord(ttl int) (string, error) {
+	name, err := z.TxtVerificationName()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %d IN TXT %s", name, ttl, quoteTXT(z.TxtVerification.Token)), nil
+}
+
+// TxtVerificationEntry returns the TXT verification record as a RecordEntry
+// with a name relative to the zone.
+func (z *Zone) TxtVerificationEntry() (*RecordEntry, error) {
+	fqdn, err := z.TxtVerificationName()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordEntry{
+		Type:   RecordTypeTXT,
+		ZoneID: z.ID,
+		Name:   ToRelative(z.Name, fqdn),
+		Value:  z.TxtVerification.Token,
+	}, nil
+}
+
+// CheckTxtVerification reports whether the TXT verification token of the
+// zone is visible through the given resolver. A nil resolver uses
+// net.DefaultResolver.
+func CheckTxtVerification(ctx context.Context, resolver TXTResolver, zone *Zone) (bool, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	name, err := zone.TxtVerificationName()
+	if err != nil {
+		return false, err
+	}
+
+	txts, err := resolver.LookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, txt := range txts {
+		if strings.Trim(txt, `"`) == zone.TxtVerification.Token {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,105 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+type testTXTResolver map[string][]string
+
+func (r testTXTResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txts, ok := r[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+
+	return txts, nil
+}
+
+func TestZoneTxtVerification(t *testing.T) {
+	as := newAssert(t)
+
+	zone := &Zone{
+		ID:   "1",
+		Name: "hetzner.com",
+		TxtVerification: &TxtVerification{
+			Name:  "_hetzner_verification",
+			Token: "abc123",
+		},
+	}
+
+	name, err := zone.TxtVerificationName()
+	if as.NoError(err) {
+		as.EqStr("_hetzner_verification.hetzner.com.", name)
+	}
+
+	rec, err := zone.TxtVerificationRecord(300)
+	if as.NoError(err) {
+		as.EqStr(`_hetzner_verification.hetzner.com. 300 IN TXT "abc123"`, rec)
+	}
+
+	zone.TxtVerification.Token = "abc\t\"123\""
+	rec, err = zone.TxtVerificationRecord(300)
+	if as.NoError(err) {
+		as.EqStr(`_hetzner_verification.hetzner.com. 300 IN TXT "abc\009\"123\""`, rec)
+	}
+	zone.TxtVerification.Token = "abc123"
+
+	entry, err := zone.TxtVerificationEntry()
+	if as.NoError(err) {
+		as.EqStr("_hetzner_verification", entry.Name)
+		as.EqStr("abc123", entry.Value)
+		as.EqStr("1", entry.ZoneID)
+		as.EqStr(string(RecordTypeTXT), string(entry.Type))
+	}
+
+	zone.TxtVerification.Name = "_hetzner_verification.hetzner.com"
+	entry, err = zone.TxtVerificationEntry()
+	if as.NoError(err) {
+		as.EqStr("_hetzner_verification", entry.Name)
+	}
+
+	zone.TxtVerification.Name = ""
+	entry, err = zone.TxtVerificationEntry()
+	if as.NoError(err) {
+		as.EqStr("@", entry.Name)
+	}
+
+	zone.TxtVerification = nil
+	if _, err := zone.TxtVerificationEntry(); !errors.Is(err, ErrNoTxtVerification) {
+		t.Errorf("expected missing verification error but got: %v", err)
+	}
+}
+
+func TestCheckTxtVerification(t *testing.T) {
+	as := newAssert(t)
+
+	resolver := testTXTResolver{
+		"_hetzner_verification.hetzner.com.": {"v=spf1 -all", "abc123"},
+		"_hetzner_verification.hetzner.de.":  {"other"},
+	}
+
+	zone := &Zone{
+		Name:            "hetzner.com",
+		TxtVerification: &TxtVerification{Name: "_hetzner_verification", Token: "abc123"},
+	}
+
+	ok, err := CheckTxtVerification(context.Background(), resolver, zone)
+	if as.NoError(err) && !ok {
+		t.Error("expected token to be visible")
+	}
+
+	zone.Name = "hetzner.de"
+	ok, err = CheckTxtVerification(context.Background(), resolver, zone)
+	if as.NoError(err) && ok {
+		t.Error("unexpected visible token")
+	}
+
+	zone.Name = "hetzner.cloud"
+	ok, err = CheckTxtVerification(context.Background(), resolver, zone)
+	if as.NoError(err) && ok {
+		t.Error("unexpected visible token")
+	}
+}
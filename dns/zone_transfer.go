@@ -0,0 +1,315 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mdns "github.com/miekg/dns"
+)
+
+// DefaultZoneTransferTimeout is the timeout of a zone transfer when the
+// context has no deadline.
+const DefaultZoneTransferTimeout = 2 * time.Minute
+
+// TSIGKey specifies a key used to authenticate zone transfers.
+type TSIGKey struct {
+	Name string
+	// Algorithm is the name of the HMAC algorithm, e.g. hmac-sha256.
+	// It defaults to hmac-sha256.
+	Algorithm string
+	// Secret is the base64 encoded secret of the key.
+	Secret string
+}
+
+func (k TSIGKey) name() string {
+	return mdns.Fqdn(strings.ToLower(k.Name))
+}
+
+func (k TSIGKey) algorithm() string {
+	if k.Algorithm == "" {
+		return mdns.HmacSHA256
+	}
+	return mdns.Fqdn(strings.ToLower(k.Algorithm))
+}
+
+// ZoneTransferOpts specifies options for transferring a zone from a primary server.
+type ZoneTransferOpts struct {
+	TSIG *TSIGKey
+}
+
+// ZoneChanges is returned by an incremental zone transfer.
+type ZoneChanges struct {
+	// Serial is the serial of the zone at the primary server.
+	Serial uint32
+	// Full is set when the primary server responded with the complete zone
+	// instead of the changes, in which case Added holds all records.
+	Full    bool
+	Added   []*RecordEntry
+	Deleted []*RecordEntry
+}
+
+// TransferZone pulls the complete zone from the primary server with AXFR.
+// The names of the returned records are relative to the zone.
+func TransferZone(ctx context.Context, zoneName string, server *PrimaryServer, opts ZoneTransferOpts) ([]*RecordEntry, error) {
+	msg := new(mdns.Msg)
+	msg.SetAxfr(mdns.Fqdn(zoneName))
+
+	rrs, err := transfer(ctx, msg, server, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The zone is framed by its SOA record, which is only kept once.
+	if len(rrs) > 1 && rrs[len(rrs)-1].Header().Rrtype == mdns.TypeSOA {
+		rrs = rrs[:len(rrs)-1]
+	}
+
+	entries := make([]*RecordEntry, 0, len(rrs))
+	for _, rr := range rrs {
+		entries = append(entries, entryFromRR(zoneName, rr))
+	}
+
+	return entries, nil
+}
+
+// TransferZoneChanges pulls the changes of the zone since the given serial
+// from the primary server with IXFR.
+func TransferZoneChanges(ctx context.Context, zoneName string, serial uint32, server *PrimaryServer, opts ZoneTransferOpts) (*ZoneChanges, error) {
+	origin := mdns.Fqdn(zoneName)
+	msg := new(mdns.Msg)
+	msg.SetIxfr(origin, serial, ".", ".")
+
+	rrs, err := transfer(ctx, msg, server, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rrs) == 0 || rrs[0].Header().Rrtype != mdns.TypeSOA {
+		return nil, errors.New("hetzner-dns: zone transfer did not start with a SOA record")
+	}
+
+	changes := &ZoneChanges{Serial: rrs[0].(*mdns.SOA).Serial}
+	if len(rrs) == 1 {
+		// The zone is up to date.
+		return changes, nil
+	}
+
+	if rrs[1].Header().Rrtype != mdns.TypeSOA {
+		changes.Full = true
+		for _, rr := range rrs[:len(rrs)-1] {
+			changes.Added = append(changes.Added, entryFromRR(origin, rr))
+		}
+		return changes, nil
+	}
+
+	// Incremental transfers consist of one sequence per version, each made of
+	// the old SOA, the deleted records, the new SOA and the added records.
+	// Records added by one version and deleted by a later one cancel out.
+	var added, deleted []mdns.RR
+	deleting := false
+	for _, rr := range rrs[1 : len(rrs)-1] {
+		if rr.Header().Rrtype == mdns.TypeSOA {
+			deleting = !deleting
+			continue
+		}
+
+		if deleting {
+			if i := indexRR(added, rr); i >= 0 {
+				added = append(added[:i], added[i+1:]...)
+			} else {
+				deleted = append(deleted, rr)
+			}
+		} else {
+			if i := indexRR(deleted, rr); i >= 0 {
+				deleted = append(deleted[:i], deleted[i+1:]...)
+			} else {
+				added = append(added, rr)
+			}
+		}
+	}
+
+	for _, rr := range deleted {
+		changes.Deleted = append(changes.Deleted, entryFromRR(origin, rr))
+	}
+	for _, rr := range added {
+		changes.Added = append(changes.Added, entryFromRR(origin, rr))
+	}
+
+	return changes, nil
+}
+
+// indexRR returns the index of the record in rrs equal to rr apart from its
+// TTL, or -1.
+func indexRR(rrs []mdns.RR, rr mdns.RR) int {
+	for i, r := range rrs {
+		if mdns.IsDuplicate(r, rr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// transfer performs the zone transfer request and returns all transferred records.
+func transfer(ctx context.Context, msg *mdns.Msg, server *PrimaryServer, opts ZoneTransferOpts) ([]mdns.RR, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultZoneTransferTimeout)
+		defer cancel()
+	}
+
+	port := server.Port
+	if port == 0 {
+		port = DefaultPrimaryServerPort
+	}
+	addr := net.JoinHostPort(server.Address, strconv.Itoa(port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline) // nolint: errcheck
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	t := &mdns.Transfer{Conn: &mdns.Conn{Conn: conn}}
+	if opts.TSIG != nil {
+		t.TsigSecret = map[string]string{opts.TSIG.name(): opts.TSIG.Secret}
+		msg.SetTsig(opts.TSIG.name(), opts.TSIG.algorithm(), 300, time.Now().Unix())
+	}
+
+	envelopes, err := t.In(msg, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var rrs []mdns.RR
+	for env := range envelopes {
+		if env.Error != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("hetzner-dns: zone transfer from %s failed: %w", addr, env.Error)
+		}
+		rrs = append(rrs, env.RR...)
+	}
+
+	return rrs, nil
+}
+
+// ZoneDrift represents the differences between a zone at its primary server
+// and the records in the Hetzner DNS.
+type ZoneDrift struct {
+	// Missing holds the records served by the primary server which are
+	// absent from the Hetzner DNS.
+	Missing []*RecordEntry
+	// Extra holds the records in the Hetzner DNS which are not served by
+	// the primary server.
+	Extra []*RecordEntry
+	// TTL holds the records of the primary server which are present in the
+	// Hetzner DNS with a different ttl.
+	TTL []*RecordEntry
+}
+
+// InSync reports whether no drift was found.
+func (d ZoneDrift) InSync() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.TTL) == 0
+}
+
+// CompareWithPrimary transfers the zone from the primary server and compares
+// it with the records of the zone in the Hetzner DNS. SOA records are not
+// compared as each server maintains its own.
+func (c RecordClient) CompareWithPrimary(ctx context.Context, zone *Zone, server *PrimaryServer, opts ZoneTransferOpts) (*ZoneDrift, error) {
+	primary, err := TransferZone(ctx, zone.Name, server, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	records, _, err := c.listAll(ctx, RecordListOpts{ZoneID: zone.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		rr    mdns.RR
+		entry *RecordEntry
+	}
+
+	hetzner := map[string]entry{}
+	for _, rec := range records {
+		if rec.Type == RecordTypeSOA {
+			continue
+		}
+
+		ttl := rec.Ttl
+		if ttl == 0 {
+			ttl = zone.Ttl
+		}
+
+		rr, err := parseRR(zone.Name, rec.Name, ttl, rec.Type, rec.Value)
+		if err != nil {
+			return nil, fmt.Errorf("hetzner-dns: record %s: %w", rec.ID, err)
+		}
+
+		hetzner[rrKey(rr)] = entry{rr, &RecordEntry{
+			Type:   rec.Type,
+			ZoneID: zone.ID,
+			Name:   rec.Name,
+			Value:  rec.Value,
+			Ttl:    &ttl,
+		}}
+	}
+
+	drift := &ZoneDrift{}
+	for _, e := range primary {
+		if e.Type == RecordTypeSOA {
+			continue
+		}
+
+		rr, err := parseRR(zone.Name, e.Name, *e.Ttl, e.Type, e.Value)
+		if err != nil {
+			return nil, err
+		}
+		e.ZoneID = zone.ID
+
+		key := rrKey(rr)
+		match, ok := hetzner[key]
+		switch {
+		case !ok:
+			drift.Missing = append(drift.Missing, e)
+		case match.rr.Header().Ttl != rr.Header().Ttl:
+			drift.TTL = append(drift.TTL, e)
+		}
+		delete(hetzner, key)
+	}
+
+	keys := make([]string, 0, len(hetzner))
+	for key := range hetzner {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		drift.Extra = append(drift.Extra, hetzner[key].entry)
+	}
+
+	return drift, nil
+}
@@ -0,0 +1,244 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+
+	mdns "github.com/miekg/dns"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+const testTSIGSecret = "c2VjcmV0LWtleS1mb3ItdGVzdGluZw=="
+
+func mustRR(t *testing.T, s string) mdns.RR {
+	rr, err := mdns.NewRR(s)
+	if err != nil {
+		t.Fatalf("invalid test record %q: %v", s, err)
+	}
+	return rr
+}
+
+// startTransferServer starts an in process primary server serving the given
+// records on AXFR and IXFR requests. IXFR requests for serial 1 receive an
+// incremental response.
+func startTransferServer(t *testing.T, rrs []mdns.RR, tsig map[string]string) *PrimaryServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	handler := mdns.HandlerFunc(func(w mdns.ResponseWriter, r *mdns.Msg) {
+		if tsig != nil && (r.IsTsig() == nil || w.TsigStatus() != nil) {
+			m := new(mdns.Msg)
+			m.SetRcode(r, mdns.RcodeRefused)
+			w.WriteMsg(m) // nolint: errcheck
+			return
+		}
+
+		answer := append(append([]mdns.RR{}, rrs...), rrs[0])
+		if r.Question[0].Qtype == mdns.TypeIXFR && r.Ns[0].(*mdns.SOA).Serial == 1 {
+			oldSOA := mdns.Copy(rrs[0]).(*mdns.SOA)
+			oldSOA.Serial = 1
+			answer = []mdns.RR{
+				rrs[0],
+				oldSOA,
+				mustRR(t, "old.hetzner.com. 300 IN A 192.0.2.99"),
+				rrs[0],
+				rrs[1],
+				rrs[0],
+			}
+		}
+
+		ch := make(chan *mdns.Envelope, 1)
+		ch <- &mdns.Envelope{RR: answer}
+		close(ch)
+
+		tr := new(mdns.Transfer)
+		tr.Out(w, r, ch) // nolint: errcheck
+		w.Close()        // nolint: errcheck
+	})
+
+	srv := &mdns.Server{Listener: l, Handler: handler, TsigSecret: tsig}
+	go srv.ActivateAndServe()            // nolint: errcheck
+	t.Cleanup(func() { srv.Shutdown() }) // nolint: errcheck
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	return &PrimaryServer{Address: host, Port: p}
+}
+
+func testZoneRRs(t *testing.T) []mdns.RR {
+	return []mdns.RR{
+		mustRR(t, "hetzner.com. 3600 IN SOA ns1.hetzner.com. dns.hetzner.com. 2 86400 10800 3600000 3600"),
+		mustRR(t, "www.hetzner.com. 300 IN A 192.0.2.1"),
+		mustRR(t, "hetzner.com. 300 IN MX 10 mail.hetzner.com."),
+		mustRR(t, `hetzner.com. 300 IN TXT "v=spf1 mx -all"`),
+		mustRR(t, "ftp.hetzner.com. 300 IN CNAME www.hetzner.com."),
+	}
+}
+
+func TestTransferZone(t *testing.T) {
+	as := newAssert(t)
+
+	server := startTransferServer(t, testZoneRRs(t), nil)
+
+	entries, err := TransferZone(context.Background(), "hetzner.com", server, ZoneTransferOpts{})
+	if as.NoError(err) && as.EqInt(5, len(entries)) {
+		as.EqStr(string(RecordTypeSOA), string(entries[0].Type))
+		as.EqStr("@", entries[0].Name)
+		as.EqStr("www", entries[1].Name)
+		as.EqStr("192.0.2.1", entries[1].Value)
+		as.EqStr("10 mail.hetzner.com.", entries[2].Value)
+		as.EqInt(300, *entries[2].Ttl)
+	}
+
+	changes, err := TransferZoneChanges(context.Background(), "hetzner.com", 1, server, ZoneTransferOpts{})
+	if as.NoError(err) {
+		as.EqInt(2, int(changes.Serial))
+		if changes.Full {
+			t.Error("expected incremental transfer")
+		}
+		if as.EqInt(1, len(changes.Deleted)) {
+			as.EqStr("old", changes.Deleted[0].Name)
+		}
+		if as.EqInt(1, len(changes.Added)) {
+			as.EqStr("www", changes.Added[0].Name)
+		}
+	}
+
+	changes, err = TransferZoneChanges(context.Background(), "hetzner.com", 0, server, ZoneTransferOpts{})
+	if as.NoError(err) {
+		if !changes.Full {
+			t.Error("expected full transfer")
+		}
+		as.EqInt(5, len(changes.Added))
+	}
+}
+
+func TestTransferZoneChangesMultipleVersions(t *testing.T) {
+	as := newAssert(t)
+
+	soa := func(serial int) mdns.RR {
+		return mustRR(t, "hetzner.com. 3600 IN SOA ns1.hetzner.com. dns.hetzner.com. "+strconv.Itoa(serial)+" 86400 10800 3600000 3600")
+	}
+
+	// Version 2 replaces a with b and adds tmp, version 3 replaces b with c,
+	// removes tmp and adds mx.
+	answer := []mdns.RR{
+		soa(3),
+		soa(1),
+		mustRR(t, "a.hetzner.com. 300 IN A 192.0.2.1"),
+		soa(2),
+		mustRR(t, "b.hetzner.com. 300 IN A 192.0.2.2"),
+		mustRR(t, "tmp.hetzner.com. 300 IN A 192.0.2.9"),
+		soa(2),
+		mustRR(t, "b.hetzner.com. 300 IN A 192.0.2.2"),
+		mustRR(t, "tmp.hetzner.com. 300 IN A 192.0.2.9"),
+		soa(3),
+		mustRR(t, "c.hetzner.com. 300 IN A 192.0.2.3"),
+		mustRR(t, "hetzner.com. 300 IN MX 10 mail.hetzner.com."),
+		soa(3),
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	handler := mdns.HandlerFunc(func(w mdns.ResponseWriter, r *mdns.Msg) {
+		ch := make(chan *mdns.Envelope, 1)
+		ch <- &mdns.Envelope{RR: answer}
+		close(ch)
+
+		tr := new(mdns.Transfer)
+		tr.Out(w, r, ch) // nolint: errcheck
+		w.Close()        // nolint: errcheck
+	})
+
+	srv := &mdns.Server{Listener: l, Handler: handler}
+	go srv.ActivateAndServe()            // nolint: errcheck
+	t.Cleanup(func() { srv.Shutdown() }) // nolint: errcheck
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+	server := &PrimaryServer{Address: host, Port: p}
+
+	changes, err := TransferZoneChanges(context.Background(), "hetzner.com", 1, server, ZoneTransferOpts{})
+	if !as.NoError(err) {
+		return
+	}
+
+	as.EqInt(3, int(changes.Serial))
+	if changes.Full {
+		t.Error("expected incremental transfer")
+	}
+	if as.EqInt(1, len(changes.Deleted)) {
+		as.EqStr("a", changes.Deleted[0].Name)
+	}
+	if as.EqInt(2, len(changes.Added)) {
+		as.EqStr("c", changes.Added[0].Name)
+		as.EqStr("@", changes.Added[1].Name)
+		as.EqStr(string(RecordTypeMX), string(changes.Added[1].Type))
+	}
+}
+
+func TestTransferZoneTSIG(t *testing.T) {
+	as := newAssert(t)
+
+	server := startTransferServer(t, testZoneRRs(t), map[string]string{"transfer.": testTSIGSecret})
+
+	_, err := TransferZone(context.Background(), "hetzner.com", server, ZoneTransferOpts{})
+	as.Error(err)
+
+	key := &TSIGKey{Name: "transfer", Secret: testTSIGSecret}
+	entries, err := TransferZone(context.Background(), "hetzner.com", server, ZoneTransferOpts{TSIG: key})
+	if as.NoError(err) {
+		as.EqInt(5, len(entries))
+	}
+}
+
+func TestRecordCompareWithPrimary(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	server := startTransferServer(t, testZoneRRs(t), nil)
+
+	env.Mux.HandleFunc(pathRecords, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.RecordListResponse{ // nolint: errcheck
+			Records: []schema.Record{
+				{ID: "1", Type: "SOA", Name: "@", Value: "hydrogen.ns.hetzner.com. dns.hetzner.com. 1 86400 10800 3600000 3600"},
+				{ID: "2", Type: "A", Name: "www", Value: "192.0.2.1", Ttl: 300},
+				{ID: "3", Type: "MX", Name: "@", Value: "10 MAIL.hetzner.com.", Ttl: 600},
+				{ID: "4", Type: "TXT", Name: "@", Value: "v=spf1 mx -all"},
+				{ID: "5", Type: "A", Name: "stale", Value: "192.0.2.2", Ttl: 300},
+			},
+		})
+	})
+
+	zone := &Zone{ID: "1", Name: "hetzner.com", Ttl: 300}
+	drift, err := env.Client.Record.CompareWithPrimary(env.Context, zone, server, ZoneTransferOpts{})
+	if !as.NoError(err) {
+		return
+	}
+
+	if drift.InSync() {
+		t.Error("expected drift")
+	}
+	if as.EqInt(1, len(drift.Missing)) {
+		as.EqStr("ftp", drift.Missing[0].Name)
+	}
+	if as.EqInt(1, len(drift.Extra)) {
+		as.EqStr("stale", drift.Extra[0].Name)
+	}
+	if as.EqInt(1, len(drift.TTL)) {
+		as.EqStr(string(RecordTypeMX), string(drift.TTL[0].Type))
+	}
+}
@@ -0,0 +1,263 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	mdns "github.com/miekg/dns"
+)
+
+// DefaultPollInterval is the interval used by WaitForVerified when no
+// positive poll interval is given.
+const DefaultPollInterval = 30 * time.Second
+
+// ErrZoneVerificationFailed is returned by WaitForVerified when the zone
+// reached the failed status.
+var ErrZoneVerificationFailed = errors.New("hetzner-dns: zone verification failed")
+
+// WaitForVerified polls the zone until its status is verified or failed, or
+// until ctx is done. The last retrieved zone is returned in all cases.
+func (c ZoneClient) WaitForVerified(ctx context.Context, zone *Zone, pollInterval time.Duration) (*Zone, *Response, error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, resp, err := c.GetByID(ctx, zone.ID)
+		if err != nil {
+			return zone, resp, err
+		}
+		zone = current
+
+		switch zone.Status {
+		case ZoneStatusVerified:
+			return zone, resp, nil
+		case ZoneStatusFailed:
+			return zone, resp, ErrZoneVerificationFailed
+		}
+
+		select {
+		case <-ctx.Done():
+			return zone, resp, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// NSResolver looks up the name servers a domain is delegated to.
+// *net.Resolver and *ParentNSResolver satisfy this interface.
+type NSResolver interface {
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+}
+
+// HostResolver looks up name servers and host addresses.
+// *net.Resolver satisfies this interface.
+type HostResolver interface {
+	NSResolver
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// ParentNSResolver looks up the delegation of a domain at its parent zone.
+// It finds the authoritative name servers of the closest enclosing zone and
+// asks them for the name servers of the domain without recursion, so the
+// answer is the referral of the parent rather than the name servers listed
+// in the zone itself or a cached answer.
+type ParentNSResolver struct {
+	// Resolver is used to find the name servers of the parent zone and their
+	// addresses. A nil Resolver uses net.DefaultResolver.
+	Resolver HostResolver
+	// Port is the port the name servers of the parent zone are queried on.
+	// It defaults to 53.
+	Port int
+	// Timeout is the timeout of a single query. It defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// LookupNS returns the name servers name is delegated to by its parent zone.
+func (r *ParentNSResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	resolver := r.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	fqdn := mdns.Fqdn(name)
+	parent, servers, err := r.parentServers(ctx, resolver, fqdn)
+	if err != nil {
+		return nil, err
+	}
+
+	port := r.Port
+	if port == 0 {
+		port = 53
+	}
+	client := &mdns.Client{Timeout: r.Timeout}
+	if client.Timeout == 0 {
+		client.Timeout = 5 * time.Second
+	}
+
+	msg := new(mdns.Msg)
+	msg.SetQuestion(fqdn, mdns.TypeNS)
+	msg.RecursionDesired = false
+
+	err = fmt.Errorf("hetzner-dns: no name server of %s answered", parent)
+	for _, server := range servers {
+		addrs, lookupErr := resolver.LookupHost(ctx, server)
+		if lookupErr != nil {
+			err = lookupErr
+			continue
+		}
+
+		for _, addr := range addrs {
+			in, _, exchangeErr := client.ExchangeContext(ctx, msg, net.JoinHostPort(addr, strconv.Itoa(port)))
+			if exchangeErr != nil {
+				err = exchangeErr
+				continue
+			}
+
+			switch in.Rcode {
+			case mdns.RcodeSuccess:
+			case mdns.RcodeNameError:
+				return nil, &net.DNSError{Err: "no such host", Name: name, Server: server, IsNotFound: true}
+			default:
+				err = fmt.Errorf("hetzner-dns: %s responded with %s", server, mdns.RcodeToString[in.Rcode])
+				continue
+			}
+
+			// A referral carries the delegation in the authority section, a
+			// parent that is authoritative for the domain as well answers it.
+			if nss := nsRecords(in.Ns, fqdn); len(nss) > 0 {
+				return nss, nil
+			}
+			if nss := nsRecords(in.Answer, fqdn); len(nss) > 0 {
+				return nss, nil
+			}
+
+			return nil, &net.DNSError{Err: "no delegation found", Name: name, Server: server, IsNotFound: true}
+		}
+	}
+
+	return nil, err
+}
+
+// parentServers returns the closest zone enclosing fqdn which has name
+// servers, together with those name servers.
+func (r *ParentNSResolver) parentServers(ctx context.Context, resolver HostResolver, fqdn string) (string, []string, error) {
+	labels := mdns.SplitDomainName(fqdn)
+	for i := 1; i <= len(labels); i++ {
+		parent := mdns.Fqdn(strings.Join(labels[i:], "."))
+
+		nss, err := resolver.LookupNS(ctx, parent)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+				continue
+			}
+			return "", nil, err
+		}
+		if len(nss) == 0 {
+			continue
+		}
+
+		servers := make([]string, 0, len(nss))
+		for _, ns := range nss {
+			servers = append(servers, ns.Host)
+		}
+		return parent, servers, nil
+	}
+
+	return "", nil, &net.DNSError{Err: "no parent zone found", Name: fqdn, IsNotFound: true}
+}
+
+// nsRecords returns the name servers of the NS records owned by fqdn.
+func nsRecords(rrs []mdns.RR, fqdn string) []*net.NS {
+	var nss []*net.NS
+	for _, rr := range rrs {
+		if ns, ok := rr.(*mdns.NS); ok && strings.EqualFold(ns.Hdr.Name, fqdn) {
+			nss = append(nss, &net.NS{Host: ns.Ns})
+		}
+	}
+
+	return nss
+}
+
+// Delegation represents the result of comparing the name servers a zone is
+// delegated to with the name servers assigned by Hetzner.
+type Delegation struct {
+	Zone *Zone
+	// NS holds the name servers the zone is delegated to.
+	NS []string
+	// Missing holds the assigned name servers absent from the delegation.
+	Missing []string
+	// Unexpected holds the delegated name servers which are neither assigned
+	// nor legacy name servers.
+	Unexpected []string
+	// Legacy holds the legacy name servers still present in the delegation.
+	Legacy []string
+}
+
+// Complete reports whether the zone is delegated to exactly the assigned
+// name servers.
+func (d Delegation) Complete() bool {
+	return len(d.Missing) == 0 && len(d.Unexpected) == 0 && len(d.Legacy) == 0
+}
+
+// CheckDelegation looks up the name servers of the zone with the given
+// resolver and compares them with Zone.NS and Zone.LegacyNS. A nil resolver
+// checks the delegation at the parent zone with a ParentNSResolver.
+func CheckDelegation(ctx context.Context, resolver NSResolver, zone *Zone) (*Delegation, error) {
+	if resolver == nil {
+		resolver = &ParentNSResolver{}
+	}
+
+	nss, err := resolver.LookupNS(ctx, zone.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation := &Delegation{Zone: zone}
+	delegated := map[string]bool{}
+	for _, ns := range nss {
+		host := normalizeHost(ns.Host)
+		delegated[host] = true
+		delegation.NS = append(delegation.NS, host)
+	}
+
+	assigned := map[string]bool{}
+	for _, ns := range zone.NS {
+		host := normalizeHost(ns)
+		assigned[host] = true
+		if !delegated[host] {
+			delegation.Missing = append(delegation.Missing, host)
+		}
+	}
+
+	legacy := map[string]bool{}
+	for _, ns := range zone.LegacyNS {
+		legacy[normalizeHost(ns)] = true
+	}
+
+	for _, host := range delegation.NS {
+		switch {
+		case assigned[host]:
+		case legacy[host]:
+			delegation.Legacy = append(delegation.Legacy, host)
+		default:
+			delegation.Unexpected = append(delegation.Unexpected, host)
+		}
+	}
+
+	return delegation, nil
+}
+
+// normalizeHost lower cases a host name and strips the trailing dot.
+func normalizeHost(host string) string {
+	return FoldName(strings.TrimSuffix(host, "."))
+}
@@ -0,0 +1,196 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	mdns "github.com/miekg/dns"
+
+	"github.com/jobstoit/hetzner-dns-go/dns/schema"
+)
+
+func TestZoneWaitForVerified(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	as := newAssert(t)
+
+	calls := 0
+	env.Mux.HandleFunc(fmt.Sprintf("%s/1", pathZones), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		status := ZoneStatusPending
+		if calls == 3 {
+			status = ZoneStatusVerified
+		}
+
+		json.NewEncoder(w).Encode(schema.ZoneResponse{ // nolint: errcheck
+			Zone: schema.Zone{ID: "1", Status: string(status)},
+		})
+	})
+
+	env.Mux.HandleFunc(fmt.Sprintf("%s/2", pathZones), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.ZoneResponse{ // nolint: errcheck
+			Zone: schema.Zone{ID: "2", Status: string(ZoneStatusFailed)},
+		})
+	})
+
+	env.Mux.HandleFunc(fmt.Sprintf("%s/3", pathZones), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(schema.ZoneResponse{ // nolint: errcheck
+			Zone: schema.Zone{ID: "3", Status: string(ZoneStatusPending)},
+		})
+	})
+
+	zone, _, err := env.Client.Zone.WaitForVerified(env.Context, &Zone{ID: "1"}, time.Millisecond)
+	if as.NoError(err) {
+		as.EqStr(string(ZoneStatusVerified), string(zone.Status))
+		as.EqInt(3, calls)
+	}
+
+	_, _, err = env.Client.Zone.WaitForVerified(env.Context, &Zone{ID: "2"}, time.Millisecond)
+	if !errors.Is(err, ErrZoneVerificationFailed) {
+		t.Errorf("expected verification failed error but got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(env.Context, 20*time.Millisecond)
+	defer cancel()
+
+	zone, _, err = env.Client.Zone.WaitForVerified(ctx, &Zone{ID: "3"}, time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded but got: %v", err)
+	}
+	as.EqStr(string(ZoneStatusPending), string(zone.Status))
+}
+
+type testNSResolver map[string][]string
+
+func (r testNSResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	hosts, ok := r[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+
+	nss := make([]*net.NS, 0, len(hosts))
+	for _, host := range hosts {
+		nss = append(nss, &net.NS{Host: host})
+	}
+
+	return nss, nil
+}
+
+func TestCheckDelegation(t *testing.T) {
+	as := newAssert(t)
+
+	resolver := testNSResolver{
+		"done.com":    {"hydrogen.ns.hetzner.com.", "oxygen.ns.hetzner.com.", "HELIUM.NS.HETZNER.DE."},
+		"partial.com": {"hydrogen.ns.hetzner.com.", "ns1.first-ns.de.", "ns.example.com."},
+	}
+
+	zone := &Zone{
+		Name:     "done.com",
+		NS:       []string{"hydrogen.ns.hetzner.com", "oxygen.ns.hetzner.com", "helium.ns.hetzner.de"},
+		LegacyNS: []string{"ns1.first-ns.de."},
+	}
+
+	delegation, err := CheckDelegation(context.Background(), resolver, zone)
+	if as.NoError(err) && !delegation.Complete() {
+		t.Errorf("expected complete delegation but got: %+v", delegation)
+	}
+
+	zone.Name = "partial.com"
+	delegation, err = CheckDelegation(context.Background(), resolver, zone)
+	if as.NoError(err) {
+		if delegation.Complete() {
+			t.Error("unexpected complete delegation")
+		}
+		as.EqInt(2, len(delegation.Missing))
+		as.EqInt(1, len(delegation.Legacy))
+		as.EqInt(1, len(delegation.Unexpected))
+		as.EqStr("ns.example.com", delegation.Unexpected[0])
+	}
+
+	zone.Name = "unknown.com"
+	_, err = CheckDelegation(context.Background(), resolver, zone)
+	as.Error(err)
+}
+
+type testHostResolver struct {
+	testNSResolver
+	hosts map[string][]string
+}
+
+func (r testHostResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, ok := r.hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return addrs, nil
+}
+
+func TestParentNSResolver(t *testing.T) {
+	as := newAssert(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	handler := mdns.HandlerFunc(func(w mdns.ResponseWriter, r *mdns.Msg) {
+		m := new(mdns.Msg)
+		m.SetReply(r)
+		switch {
+		case r.RecursionDesired:
+			m.Rcode = mdns.RcodeRefused
+		case r.Question[0].Name == "hetzner.com.":
+			m.Ns = []mdns.RR{
+				mustRR(t, "hetzner.com. 172800 IN NS hydrogen.ns.hetzner.com."),
+				mustRR(t, "hetzner.com. 172800 IN NS oxygen.ns.hetzner.com."),
+			}
+		default:
+			m.Rcode = mdns.RcodeNameError
+		}
+		w.WriteMsg(m) // nolint: errcheck
+	})
+
+	srv := &mdns.Server{PacketConn: conn, Handler: handler}
+	go srv.ActivateAndServe()            // nolint: errcheck
+	t.Cleanup(func() { srv.Shutdown() }) // nolint: errcheck
+
+	_, port, _ := net.SplitHostPort(conn.LocalAddr().String())
+	p, _ := strconv.Atoi(port)
+
+	resolver := &ParentNSResolver{
+		Resolver: testHostResolver{
+			// The zone itself lists different name servers than its parent.
+			testNSResolver: testNSResolver{
+				"hetzner.com.": {"ns.example.com."},
+				"com.":         {"a.gtld-servers.net."},
+			},
+			hosts: map[string][]string{"a.gtld-servers.net.": {"127.0.0.1"}},
+		},
+		Port: p,
+	}
+
+	zone := &Zone{
+		Name: "hetzner.com",
+		NS:   []string{"hydrogen.ns.hetzner.com", "oxygen.ns.hetzner.com"},
+	}
+
+	delegation, err := CheckDelegation(context.Background(), resolver, zone)
+	if as.NoError(err) && !delegation.Complete() {
+		t.Errorf("expected complete delegation but got: %+v", delegation)
+	}
+
+	_, err = resolver.LookupNS(context.Background(), "unknown.com")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("expected not found error but got: %v", err)
+	}
+}
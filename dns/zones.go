@@ -56,17 +56,27 @@ type ZoneClient struct {
 //  ZoneListOptions specifies options for listing zones.
 type ZoneListOpts struct {
 	ListOpts
-	Name       string
+	Name string
+	// SearchName matches zones containing the name. Unicode labels are
+	// converted to A-labels and therefore only match whole labels.
 	SearchName string
 }
 
 func (l ZoneListOpts) values() url.Values {
 	vals := l.ListOpts.values()
 	if l.Name != "" {
-		vals.Add("name", l.Name)
+		name, err := ToASCII(l.Name)
+		if err != nil {
+			name = l.Name
+		}
+		vals.Add("name", name)
 	}
 	if l.SearchName != "" {
-		vals.Add("search_name", l.SearchName)
+		search, err := ToASCII(l.SearchName)
+		if err != nil {
+			search = l.SearchName
+		}
+		vals.Add("search_name", search)
 	}
 	return vals
 }
@@ -125,12 +135,27 @@ func (o ZoneCreateOpts) Validate() error {
 
 // Create creates a new zone.
 func (c ZoneClient) Create(ctx context.Context, opts ZoneCreateOpts) (*Zone, *Response, error) {
+	zone, resp, err := c.create(ctx, opts)
+	audited := zone
+	if audited == nil {
+		audited = &Zone{Name: opts.Name}
+	}
+	c.client.audit(AuditEvent{Operation: AuditZoneCreate, Request: opts, After: zone}, audited, resp, err)
+	return zone, resp, err
+}
+
+func (c ZoneClient) create(ctx context.Context, opts ZoneCreateOpts) (*Zone, *Response, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, nil, err
 	}
 
+	name, err := asciiName(opts.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var reqBody schema.ZoneCreateRequest
-	reqBody.Name = opts.Name
+	reqBody.Name = name
 	reqBody.Ttl = opts.Ttl
 
 	reqBodyData, err := json.Marshal(reqBody)
@@ -156,6 +181,9 @@ func (c ZoneClient) Create(ctx context.Context, opts ZoneCreateOpts) (*Zone, *Re
 type ZoneUpdateOpts struct {
 	Name string
 	Ttl  *int
+	// IfUnmodified refuses the update with ErrConflict when the zone was
+	// modified since the given zone was read.
+	IfUnmodified bool
 }
 
 // Validate checks if the options are valid.
@@ -169,12 +197,34 @@ func (o ZoneUpdateOpts) Validate() error {
 
 // Update updates a zone.
 func (c ZoneClient) Update(ctx context.Context, zone *Zone, opts ZoneUpdateOpts) (*Zone, *Response, error) {
+	before := c.auditedZone(ctx, zone.ID)
+	updated, resp, err := c.update(ctx, zone, opts)
+	c.client.audit(AuditEvent{Operation: AuditZoneUpdate, Request: opts, Before: before, After: updated}, zone, resp, err)
+	return updated, resp, err
+}
+
+func (c ZoneClient) update(ctx context.Context, zone *Zone, opts ZoneUpdateOpts) (*Zone, *Response, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, nil, err
 	}
 
+	name, err := asciiName(opts.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.IfUnmodified {
+		current, resp, err := c.GetByID(ctx, zone.ID)
+		if err != nil {
+			return nil, resp, err
+		}
+		if err := checkUnmodified(zone.Modified, current.Modified); err != nil {
+			return nil, resp, err
+		}
+	}
+
 	var reqBody schema.ZoneUpdateRequest
-	reqBody.Name = opts.Name
+	reqBody.Name = name
 	reqBody.Ttl = opts.Ttl
 
 	reqBodyData, err := json.Marshal(reqBody)
@@ -198,6 +248,28 @@ func (c ZoneClient) Update(ctx context.Context, zone *Zone, opts ZoneUpdateOpts)
 
 // Delete deletes a zone.
 func (c ZoneClient) Delete(ctx context.Context, zone *Zone) (*Response, error) {
+	before := c.auditedZone(ctx, zone.ID)
+	resp, err := c.delete(ctx, zone)
+	c.client.audit(AuditEvent{Operation: AuditZoneDelete, Before: before}, zone, resp, err)
+	return resp, err
+}
+
+// auditedZone returns the zone as stored in the API for an audit event, or
+// nil when the client has no audit sinks or the zone can't be read.
+func (c ZoneClient) auditedZone(ctx context.Context, id string) *Zone {
+	if !c.client.auditing() || id == "" || isDryRunID(id) {
+		return nil
+	}
+
+	zone, _, err := c.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+
+	return zone
+}
+
+func (c ZoneClient) delete(ctx context.Context, zone *Zone) (*Response, error) {
 	req, err := c.client.NewRequest(ctx, "DELETE", fmt.Sprintf("%s/%s", pathZones, zone.ID), nil)
 	if err != nil {
 		return nil, err
@@ -208,7 +280,25 @@ func (c ZoneClient) Delete(ctx context.Context, zone *Zone) (*Response, error) {
 
 // Import imports a zone file in text/plain format.
 func (c ZoneClient) Import(ctx context.Context, zone *Zone, file io.Reader) (*Zone, *Response, error) {
-	req, err := c.client.NewRequest(ctx, "POST", fmt.Sprintf("%s/%s/import", pathZones, zone.ID), file)
+	before := c.auditedZone(ctx, zone.ID)
+	imported, resp, err := c.importFile(ctx, zone, file)
+	c.client.audit(AuditEvent{Operation: AuditZoneImport, Before: before, After: imported}, zone, resp, err)
+	return imported, resp, err
+}
+
+func (c ZoneClient) importFile(ctx context.Context, zone *Zone, file io.Reader) (*Zone, *Response, error) {
+	if c.client.dryRun {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := checkZoneFile(zone.Name, data); err != nil {
+			return nil, nil, err
+		}
+		file = bytes.NewReader(data)
+	}
+
+	req, err := c.client.newTransferRequest(ctx, "POST", fmt.Sprintf("%s/%s/import", pathZones, zone.ID), file)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -225,7 +315,7 @@ func (c ZoneClient) Import(ctx context.Context, zone *Zone, file io.Reader) (*Zo
 
 // Export exports a zone in text/plain format.
 func (c ZoneClient) Export(ctx context.Context, zone *Zone) (io.Reader, *Response, error) {
-	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("%s/%s/export", pathZones, zone.ID), nil)
+	req, err := c.client.newTransferRequest(ctx, "GET", fmt.Sprintf("%s/%s/export", pathZones, zone.ID), nil)
 	if err != nil {
 		return nil, nil, err
 	}
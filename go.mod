@@ -1,3 +1,14 @@
 module github.com/jobstoit/hetzner-dns-go
 
 go 1.19
+
+require (
+	github.com/miekg/dns v1.1.50
+	golang.org/x/net v0.17.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+)